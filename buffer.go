@@ -0,0 +1,95 @@
+package golog
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultBufferSize is default buffer size of BufferedWriteSyncer.
+	DefaultBufferSize = 256 << 10
+	// DefaultFlushInterval is default flush interval of BufferedWriteSyncer.
+	DefaultFlushInterval = 30 * time.Second
+)
+
+// BufferedWriteSyncer is a writer which buffers writes in memory and
+// flushes them when the buffer is full, periodically, or on Sync.
+// It is safe for concurrent use.
+type BufferedWriteSyncer struct {
+	mu   sync.Mutex
+	w    io.Writer
+	buf  *bufio.Writer
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ Syncer = (*BufferedWriteSyncer)(nil)
+
+// NewBufferedWriteSyncer new a buffered writer which wraps w, DefaultBufferSize
+// and DefaultFlushInterval are used if size or interval is not positive.
+func NewBufferedWriteSyncer(w io.Writer, size int, interval time.Duration) *BufferedWriteSyncer {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	if interval <= 0 {
+		interval = DefaultFlushInterval
+	}
+	s := &BufferedWriteSyncer{
+		w:    w,
+		buf:  bufio.NewWriterSize(w, size),
+		done: make(chan struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.run(interval)
+	return s
+}
+
+func (s *BufferedWriteSyncer) run(interval time.Duration) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			_ = s.buf.Flush()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// Write writes p into buffer.
+func (s *BufferedWriteSyncer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+// Sync flushes buffer and syncs the wrapped writer.
+func (s *BufferedWriteSyncer) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return joinErrors(s.buf.Flush(), syncWriter(s.w))
+}
+
+// Close stops periodic flushing and flushes buffer, the wrapped writer is not closed.
+func (s *BufferedWriteSyncer) Close() error {
+	s.mu.Lock()
+	select {
+	case <-s.done:
+		s.mu.Unlock()
+		return nil
+	default:
+		close(s.done)
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return s.Sync()
+}
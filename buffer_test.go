@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Test that BufferedWriteSyncer properly buffers writes until Sync.
+func TestBufferedWriteSyncer(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+	w := NewBufferedWriteSyncer(&buf, 1024, time.Hour)
+	log := NewStdLogger(w)
+
+	log.Log(LevelInfo, "k1", "v1")
+	if got := buf.String(); got != "" {
+		t.Errorf("buf.String() before Sync = %q want empty", got)
+	}
+	if err := Sync(log); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if got, want := buf.String(), `INFO, "k1": "v1"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+
+	log.Log(LevelInfo, "k2", "v2")
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() error: %v", err)
+	}
+	if got, want := buf.String(), `INFO, "k1": "v1"`+"\n"+`INFO, "k2": "v2"`+"\n"; got != want {
+		t.Errorf("buf.String() after Close = %q want %q", got, want)
+	}
+}
+
+// Test that BufferedWriteSyncer properly flushes periodically.
+func TestBufferedWriteSyncerInterval(t *testing.T) {
+	t.Parallel()
+
+	var buf syncBuffer
+	w := NewBufferedWriteSyncer(&buf, 1024, 10*time.Millisecond)
+	defer w.Close()
+
+	_, _ = w.Write([]byte("hello"))
+	deadline := time.Now().Add(time.Second)
+	for buf.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got, want := buf.String(), "hello"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
@@ -0,0 +1,304 @@
+package golog
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config describes a logger built by Build.
+type Config struct {
+	// Level is the minimum level of all sinks, one of debug, info, warn, error
+	// and fatal, default is debug.
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Timestamp control whether if appending timestamp into log.
+	Timestamp bool `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+	// TimestampFormat is format of timestamp, default is DefaultTimestampFormat.
+	TimestampFormat string `json:"timestampFormat,omitempty" yaml:"timestampFormat,omitempty"`
+	// Caller control whether if appending caller information into log.
+	Caller bool `json:"caller,omitempty" yaml:"caller,omitempty"`
+	// CallerFullPath control whether if recording the full path of log source file.
+	CallerFullPath bool `json:"callerFullPath,omitempty" yaml:"callerFullPath,omitempty"`
+	// Fields are static kv pairs appended into log, sorted by key.
+	Fields map[string]interface{} `json:"fields,omitempty" yaml:"fields,omitempty"`
+	// Sampling samples logs with the same level and message if not nil.
+	Sampling *SamplingConfig `json:"sampling,omitempty" yaml:"sampling,omitempty"`
+	// Sinks are outputs of log, default is a term sink to stderr.
+	Sinks []SinkConfig `json:"sinks,omitempty" yaml:"sinks,omitempty"`
+}
+
+// SamplingConfig describes the arguments of FilterSample.
+type SamplingConfig struct {
+	// Tick is a duration string such as "1s", default is "1s".
+	Tick string `json:"tick,omitempty" yaml:"tick,omitempty"`
+	// First is the number of logs kept within each tick, it must be positive.
+	First int `json:"first,omitempty" yaml:"first,omitempty"`
+	// Thereafter keeps every thereafter-th log after first, all logs after
+	// first are discarded if it is not positive.
+	Thereafter int `json:"thereafter,omitempty" yaml:"thereafter,omitempty"`
+}
+
+// SinkConfig describes an output of log.
+type SinkConfig struct {
+	// Type is one of stdout, stderr and file.
+	Type string `json:"type" yaml:"type"`
+	// Encoder is one of term, std, json and logfmt, default is term.
+	Encoder string `json:"encoder,omitempty" yaml:"encoder,omitempty"`
+	// Level is the minimum level of this sink.
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Color control whether if term encoder output with color.
+	Color bool `json:"color,omitempty" yaml:"color,omitempty"`
+	// Path is the file path of file sink.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// MaxSize is the maximum size in bytes of file sink before it gets rotated,
+	// the file is never rotated if it is not positive.
+	MaxSize int64 `json:"maxSize,omitempty" yaml:"maxSize,omitempty"`
+	// MaxBackups is the maximum number of rotated files to keep.
+	MaxBackups int `json:"maxBackups,omitempty" yaml:"maxBackups,omitempty"`
+}
+
+// ParseConfig parse config from JSON or YAML data.
+func ParseConfig(data []byte) (Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("golog: parse config: %w", err)
+	}
+	return cfg, nil
+}
+
+// LoadConfigFile parse config from JSON or YAML file.
+func LoadConfigFile(filename string) (Config, error) {
+	data, err := os.ReadFile(filename) //nolint:gosec
+	if err != nil {
+		return Config{}, fmt.Errorf("golog: load config: %w", err)
+	}
+	return ParseConfig(data)
+}
+
+// LoadConfigEnv load config from environment variables with prefix.
+//
+// The whole config is read from ${prefix}_CONFIG (JSON or YAML) or from the
+// file named by ${prefix}_CONFIG_FILE, then it is overridden by
+// ${prefix}_LEVEL, ${prefix}_ENCODER, ${prefix}_TIMESTAMP and ${prefix}_CALLER.
+// The encoder override applies to all sinks.
+func LoadConfigEnv(prefix string) (Config, error) {
+	var cfg Config
+	var err error
+	if s, ok := os.LookupEnv(prefix + "_CONFIG"); ok {
+		cfg, err = ParseConfig([]byte(s))
+	} else if s, ok := os.LookupEnv(prefix + "_CONFIG_FILE"); ok {
+		cfg, err = LoadConfigFile(s)
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if s, ok := os.LookupEnv(prefix + "_LEVEL"); ok {
+		cfg.Level = s
+	}
+	if s, ok := os.LookupEnv(prefix + "_ENCODER"); ok {
+		if len(cfg.Sinks) == 0 {
+			cfg.Sinks = []SinkConfig{{Type: "stderr"}}
+		}
+		for i := range cfg.Sinks {
+			cfg.Sinks[i].Encoder = s
+		}
+	}
+	if err := lookupEnvBool(prefix+"_TIMESTAMP", &cfg.Timestamp); err != nil {
+		return cfg, err
+	}
+	if err := lookupEnvBool(prefix+"_CALLER", &cfg.Caller); err != nil {
+		return cfg, err
+	}
+	return cfg, nil
+}
+
+// lookupEnvBool parses environment variable name into p if it is present.
+func lookupEnvBool(name string, p *bool) error {
+	s, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("golog: parse env %s: %w", name, err)
+	}
+	*p = v
+	return nil
+}
+
+type multiCloser []io.Closer
+
+func (c multiCloser) Close() error {
+	errs := make([]error, 0, len(c))
+	for _, closer := range c {
+		errs = append(errs, closer.Close())
+	}
+	return joinErrors(errs...)
+}
+
+// Build builds a logger from config, the returned closer closes all
+// the files opened by the logger.
+func Build(cfg Config) (Logger, io.Closer, error) {
+	filters, err := buildFilters(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	logger, closer, err := buildSinks(cfg.Sinks)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(filters) > 0 {
+		logger = WithFilter(logger, filters...)
+	}
+	if handlers := buildHandlers(cfg); len(handlers) > 0 {
+		logger = WithHandler(logger, handlers...)
+	}
+	return logger, closer, nil
+}
+
+func buildFilters(cfg Config) ([]Filter, error) {
+	var filters []Filter
+	if cfg.Level != "" {
+		level, err := parseConfigLevel(cfg.Level)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, FilterLevel(level))
+	}
+	if cfg.Sampling != nil {
+		tick := time.Second
+		if cfg.Sampling.Tick != "" {
+			var err error
+			if tick, err = time.ParseDuration(cfg.Sampling.Tick); err != nil {
+				return nil, fmt.Errorf("golog: parse sampling tick: %w", err)
+			}
+		}
+		if cfg.Sampling.First <= 0 {
+			return nil, fmt.Errorf("golog: sampling first must be positive, got %d", cfg.Sampling.First)
+		}
+		filters = append(filters, FilterSample(tick, cfg.Sampling.First, cfg.Sampling.Thereafter))
+	}
+	return filters, nil
+}
+
+// parseConfigLevel parses level name, unlike ParseLevel it reports unknown names.
+func parseConfigLevel(name string) (Level, error) {
+	level := ParseLevel(name)
+	if level.String() != strings.ToUpper(name) {
+		return level, fmt.Errorf("golog: unknown level %q", name)
+	}
+	return level, nil
+}
+
+func buildHandlers(cfg Config) []Handler {
+	var handlers []Handler
+	if len(cfg.Fields) > 0 {
+		keys := make([]string, 0, len(cfg.Fields))
+		for k := range cfg.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]interface{}, 0, 2*len(keys))
+		for _, k := range keys {
+			fields = append(fields, k, cfg.Fields[k])
+		}
+		handlers = append(handlers, HandlerFields(fields...))
+	}
+	if cfg.Timestamp {
+		format := cfg.TimestampFormat
+		if format == "" {
+			format = DefaultTimestampFormat
+		}
+		handlers = append(handlers, HandlerTimestamp(DefaultTimestampKeyName, format, DefaultTimestampNowFunc))
+	}
+	if cfg.Caller {
+		handlers = append(handlers, HandlerCaller(DefaultCallerKeyName, DefaultCallerDepth, cfg.CallerFullPath))
+	}
+	return handlers
+}
+
+// buildSinks builds a logger routes logs to sinks.
+func buildSinks(sinks []SinkConfig) (Logger, io.Closer, error) {
+	if len(sinks) == 0 {
+		sinks = []SinkConfig{{Type: "stderr"}}
+	}
+	for _, sink := range sinks {
+		if sink.Level == "" {
+			continue
+		}
+		if _, err := parseConfigLevel(sink.Level); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var closer multiCloser
+	rules := make([]RouteRule, 0, len(sinks))
+	for _, sink := range sinks {
+		logger, c, err := buildSink(sink)
+		if err != nil {
+			_ = closer.Close()
+			return nil, nil, err
+		}
+		if c != nil {
+			closer = append(closer, c)
+		}
+		if sink.Level != "" {
+			rules = append(rules, When(FilterLevel(ParseLevel(sink.Level)), logger))
+		} else {
+			rules = append(rules, Default(logger))
+		}
+	}
+
+	if len(rules) == 1 && rules[0].filter == nil {
+		return rules[0].logger, closer, nil
+	}
+	return RouteAll(rules...), closer, nil
+}
+
+func buildSink(sink SinkConfig) (Logger, io.Closer, error) {
+	var w io.Writer
+	var closer io.Closer
+	switch strings.ToLower(sink.Type) {
+	case "stdout":
+		w = os.Stdout
+	case "stderr":
+		w = os.Stderr
+	case "file":
+		if sink.Path == "" {
+			return nil, nil, errors.New("golog: file sink without path")
+		}
+		rw, err := NewRotateWriter(sink.Path, sink.MaxSize, sink.MaxBackups)
+		if err != nil {
+			return nil, nil, fmt.Errorf("golog: open file sink: %w", err)
+		}
+		w, closer = rw, rw
+	default:
+		return nil, nil, fmt.Errorf("golog: unknown sink type %q", sink.Type)
+	}
+
+	var logger Logger
+	switch strings.ToLower(sink.Encoder) {
+	case "", "term":
+		logger = NewTermLogger(w, sink.Color)
+	case "std":
+		logger = NewStdLogger(w)
+	case "json":
+		logger = NewJSONLogger(w)
+	case "logfmt":
+		logger = NewLogfmtLogger(w)
+	default:
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return nil, nil, fmt.Errorf("golog: unknown encoder %q", sink.Encoder)
+	}
+	return logger, closer, nil
+}
@@ -0,0 +1,157 @@
+package golog
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test that ParseConfig properly parse JSON and YAML config.
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{
+			name: "JSON",
+			data: `{"level": "warn", "caller": true, "sinks": [{"type": "file", "path": "a.log", "encoder": "json"}]}`,
+		},
+		{
+			name: "YAML",
+			data: "level: warn\ncaller: true\nsinks:\n  - type: file\n    path: a.log\n    encoder: json\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("ParseConfig() error: %v", err)
+			}
+			if cfg.Level != "warn" || !cfg.Caller || len(cfg.Sinks) != 1 ||
+				cfg.Sinks[0].Type != "file" || cfg.Sinks[0].Path != "a.log" || cfg.Sinks[0].Encoder != "json" {
+				t.Errorf("ParseConfig() = %+v", cfg)
+			}
+		})
+	}
+}
+
+// Test that LoadConfigEnv properly load config from environment variables.
+func TestLoadConfigEnv(t *testing.T) {
+	t.Setenv("GOLOGTEST_CONFIG", `{"sinks": [{"type": "stdout"}, {"type": "stderr"}]}`)
+	t.Setenv("GOLOGTEST_LEVEL", "error")
+	t.Setenv("GOLOGTEST_ENCODER", "logfmt")
+	t.Setenv("GOLOGTEST_TIMESTAMP", "true")
+
+	cfg, err := LoadConfigEnv("GOLOGTEST")
+	if err != nil {
+		t.Fatalf("LoadConfigEnv() error: %v", err)
+	}
+	if cfg.Level != "error" || !cfg.Timestamp || len(cfg.Sinks) != 2 ||
+		cfg.Sinks[0].Encoder != "logfmt" || cfg.Sinks[1].Encoder != "logfmt" {
+		t.Errorf("LoadConfigEnv() = %+v", cfg)
+	}
+
+	t.Setenv("GOLOGTEST_CALLER", "what")
+	t.Setenv("GOLOGTEST_TIMESTAMP", "when")
+	for i := 0; i < 10; i++ {
+		_, err := LoadConfigEnv("GOLOGTEST")
+		if err == nil || !strings.Contains(err.Error(), "GOLOGTEST_TIMESTAMP") {
+			t.Fatalf("LoadConfigEnv() with invalid bools error = %v want GOLOGTEST_TIMESTAMP", err)
+		}
+	}
+}
+
+// Test that Build properly builds logger from config.
+func TestBuild(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	all := filepath.Join(dir, "all.log")
+	errs := filepath.Join(dir, "error.log")
+	logger, closer, err := Build(Config{
+		Level:  "info",
+		Caller: true,
+		Fields: map[string]interface{}{"app": "test"},
+		Sinks: []SinkConfig{
+			{Type: "file", Path: all, Encoder: "logfmt"},
+			{Type: "file", Path: errs, Encoder: "json", Level: "error"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Build() error: %v", err)
+	}
+
+	logger.Log(LevelDebug, DefaultMsgKey, "m1")
+	logger.Log(LevelInfo, DefaultMsgKey, "m2")
+	logger.Log(LevelError, DefaultMsgKey, "m3")
+	if err := closer.Close(); err != nil {
+		t.Fatalf("closer.Close() error: %v", err)
+	}
+
+	want := "level=INFO msg=m2 app=test caller=config_test.go:87\n" +
+		"level=ERROR msg=m3 app=test caller=config_test.go:88\n"
+	if got := readFile(t, all); got != want {
+		t.Errorf("all.log = %q want %q", got, want)
+	}
+	want = `{"level":"ERROR","msg":"m3","app":"test","caller":"config_test.go:88"}` + "\n"
+	if got := readFile(t, errs); got != want {
+		t.Errorf("error.log = %q want %q", got, want)
+	}
+}
+
+// Test that Build properly reports invalid config.
+func TestBuildError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "Unknown sink",
+			cfg:  Config{Sinks: []SinkConfig{{Type: "kafka"}}},
+			want: "unknown sink type",
+		},
+		{
+			name: "Unknown encoder",
+			cfg:  Config{Sinks: []SinkConfig{{Type: "stdout", Encoder: "xml"}}},
+			want: "unknown encoder",
+		},
+		{
+			name: "File without path",
+			cfg:  Config{Sinks: []SinkConfig{{Type: "file"}}},
+			want: "without path",
+		},
+		{
+			name: "Unknown level",
+			cfg:  Config{Level: "verbose"},
+			want: "unknown level",
+		},
+		{
+			name: "Unknown sink level",
+			cfg:  Config{Sinks: []SinkConfig{{Type: "stdout", Level: "wran"}}},
+			want: "unknown level",
+		},
+		{
+			name: "Invalid tick",
+			cfg:  Config{Sampling: &SamplingConfig{Tick: "x"}},
+			want: "sampling tick",
+		},
+		{
+			name: "Sampling without first",
+			cfg:  Config{Sampling: &SamplingConfig{Thereafter: 100}},
+			want: "sampling first",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := Build(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Build() error = %v want contains %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package golog
 
 import (
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -37,9 +38,12 @@ type decoratedLogger struct {
 	logger  Logger
 	filter  []Filter
 	handler []Handler
+	hook    []Hook
 }
 
 func (l *decoratedLogger) Log(level Level, kvs ...interface{}) {
+	// expand groups so filters and handlers see kv pairs
+	kvs = expandGroups(kvs)
 	for _, f := range l.filter {
 		if f(level, kvs) {
 			return
@@ -48,16 +52,34 @@ func (l *decoratedLogger) Log(level Level, kvs ...interface{}) {
 	for _, f := range l.handler {
 		kvs = f(level, kvs)
 	}
+	for _, h := range l.hook {
+		if hookLevel(h, level) {
+			fireHook(h, level, kvs, l.logger)
+		}
+	}
 	l.logger.Log(level, kvs...)
 }
 
+// Sync flushes buffered logs of the decorated logger and hooks.
+func (l *decoratedLogger) Sync() error {
+	errs := make([]error, 0, len(l.hook)+1)
+	for _, h := range l.hook {
+		if s, ok := h.(Syncer); ok {
+			errs = append(errs, s.Sync())
+		}
+	}
+	errs = append(errs, Sync(l.logger))
+	return joinErrors(errs...)
+}
+
 // WithFilter decorate logger with filters
 func WithFilter(logger Logger, filter ...Filter) Logger {
 	if l, ok := logger.(*decoratedLogger); ok {
 		return &decoratedLogger{
 			logger:  l.logger,
-			filter:  append(l.filter, filter...),
+			filter:  append(l.filter[:len(l.filter):len(l.filter)], filter...),
 			handler: l.handler,
+			hook:    l.hook,
 		}
 	}
 	return &decoratedLogger{logger: logger, filter: filter}
@@ -69,12 +91,27 @@ func WithHandler(logger Logger, handler ...Handler) Logger {
 		return &decoratedLogger{
 			logger:  l.logger,
 			filter:  l.filter,
-			handler: append(l.handler, handler...),
+			handler: append(l.handler[:len(l.handler):len(l.handler)], handler...),
+			hook:    l.hook,
 		}
 	}
 	return &decoratedLogger{logger: logger, handler: handler}
 }
 
+// WithHook decorate logger with hooks, hooks are fired after all filters
+// and handlers of the decorated logger and before the log is written.
+func WithHook(logger Logger, hook ...Hook) Logger {
+	if l, ok := logger.(*decoratedLogger); ok {
+		return &decoratedLogger{
+			logger:  l.logger,
+			filter:  l.filter,
+			handler: l.handler,
+			hook:    append(l.hook[:len(l.hook):len(l.hook)], hook...),
+		}
+	}
+	return &decoratedLogger{logger: logger, hook: hook}
+}
+
 // FilterLevel filter log level less than specific level.
 func FilterLevel(l Level) Filter {
 	return func(level Level, kvs []interface{}) bool {
@@ -82,6 +119,13 @@ func FilterLevel(l Level) Filter {
 	}
 }
 
+// HandlerFields append static kv pairs into log.
+func HandlerFields(fields ...interface{}) Handler {
+	return func(level Level, kvs []interface{}) []interface{} {
+		return append(kvs, fields...)
+	}
+}
+
 // HandlerTimestamp append timestamp information into log.
 func HandlerTimestamp(keyName, valueFormat string, nowFunc func() time.Time) Handler {
 	return func(level Level, kvs []interface{}) []interface{} {
@@ -92,14 +136,7 @@ func HandlerTimestamp(keyName, valueFormat string, nowFunc func() time.Time) Han
 // HandlerCaller append caller information into log.
 func HandlerCaller(keyName string, depth int, withFullPath bool) Handler {
 	return func(level Level, kvs []interface{}) []interface{} {
-		_, file, line, _ := runtime.Caller(depth)
-
-		// skip caller in file helper.go
-		for strings.HasSuffix(file, "/helper.go") {
-			depth++
-			_, file, line, _ = runtime.Caller(depth)
-		}
-
+		file, line := caller(depth)
 		if !withFullPath {
 			index := strings.LastIndexByte(file, '/')
 			file = file[index+1:]
@@ -108,3 +145,26 @@ func HandlerCaller(keyName string, depth int, withFullPath bool) Handler {
 		return append(kvs, keyName, value)
 	}
 }
+
+// caller returns file and line of the caller at depth relative to the caller
+// of caller, frames in non-test source files of golog, such as helper.go,
+// named.go and group.go, are skipped.
+func caller(depth int) (string, int) {
+	_, file, line, _ := runtime.Caller(depth + 1)
+	for isPackageFile(file) {
+		depth++
+		_, file, line, _ = runtime.Caller(depth + 1)
+	}
+	return file, line
+}
+
+// packageDir is the directory of this package, used to skip frames of golog.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// isPackageFile reports whether file is a non-test source file of golog.
+func isPackageFile(file string) bool {
+	return filepath.Dir(file) == packageDir && !strings.HasSuffix(file, "_test.go")
+}
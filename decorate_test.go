@@ -126,3 +126,24 @@ func TestHandlerDefaultCaller(t *testing.T) {
 		t.Errorf("buf.String() = %q want = %q", got, want)
 	}
 }
+
+// Test that loggers decorated from the same logger do not share handlers.
+func TestWithHandlerSiblings(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	base := NewStdLogger(&buf)
+	for i := 0; i < 3; i++ {
+		base = WithHandler(base, HandlerFields(fmt.Sprint("k", i), i))
+	}
+	l1 := WithHandler(base, HandlerFields("a", 1))
+	l2 := WithHandler(base, HandlerFields("b", 2))
+
+	l1.Log(LevelInfo)
+	l2.Log(LevelInfo)
+	want := `INFO, "k0": "0", "k1": "1", "k2": "2", "a": "1"` + "\n" +
+		`INFO, "k0": "0", "k1": "1", "k2": "2", "b": "2"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want = %q", got, want)
+	}
+}
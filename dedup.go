@@ -0,0 +1,186 @@
+package golog
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDedupWindow is default window within which repeated logs are collapsed.
+var DefaultDedupWindow = 10 * time.Second
+
+// DedupOption is option for Dedup.
+type DedupOption func(*dedupLogger)
+
+// DedupWindow sets the window within which repeated logs are collapsed,
+// the window starts from the first log of the repetition.
+func DedupWindow(window time.Duration) DedupOption {
+	return func(l *dedupLogger) {
+		l.window = window
+	}
+}
+
+// DedupKVs sets whether all kvs are compared instead of only level and message,
+// all kvs of logs without message are always compared.
+func DedupKVs(compare bool) DedupOption {
+	return func(l *dedupLogger) {
+		l.compareKVs = compare
+	}
+}
+
+type dedupLogger struct {
+	logger     Logger
+	window     time.Duration
+	compareKVs bool
+	now        func() time.Time
+
+	mu     sync.Mutex
+	level  Level
+	key    string
+	msg    interface{}
+	first  time.Time
+	last   time.Time
+	repeat int
+	timer  *time.Timer
+}
+
+func (l *dedupLogger) recordKey(kvs []interface{}) (string, interface{}) {
+	var msg interface{}
+	found := false
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if k, ok := kvs[i].(string); ok && k == DefaultMsgKey {
+			msg, found = kvs[i+1], true
+			break
+		}
+	}
+
+	var sb strings.Builder
+	if found && !l.compareKVs {
+		writeDedupKey(&sb, msg)
+		return sb.String(), msg
+	}
+	for _, v := range kvs {
+		writeDedupKey(&sb, v)
+	}
+	return sb.String(), msg
+}
+
+// writeDedupKey writes v to the key of a log, Lazy values are not evaluated
+// and are all the same, groups are written with their kvs.
+func writeDedupKey(sb *strings.Builder, v interface{}) {
+	switch v := v.(type) {
+	case Lazy:
+		_, _ = sb.WriteString("\x01")
+	case GroupValue:
+		_, _ = sb.WriteString(v.Name)
+		_ = sb.WriteByte('{')
+		for _, v := range v.KVs {
+			writeDedupKey(sb, v)
+		}
+		_ = sb.WriteByte('}')
+	default:
+		fmt.Fprintf(sb, "%v", v)
+	}
+	_ = sb.WriteByte(0)
+}
+
+func (l *dedupLogger) Log(level Level, kvs ...interface{}) {
+	key, msg := l.recordKey(kvs)
+	now := l.now()
+
+	l.mu.Lock()
+	if l.timer != nil && level == l.level && key == l.key && now.Sub(l.first) < l.window {
+		l.repeat++
+		l.last = now
+		l.mu.Unlock()
+		return
+	}
+
+	summary := l.flush()
+	l.level, l.key, l.msg = level, key, msg
+	l.first, l.last = now, now
+	var timer *time.Timer
+	timer = time.AfterFunc(l.window, func() {
+		l.mu.Lock()
+		// the repetition may have been flushed before the timer fired
+		var summary Record
+		if l.timer == timer {
+			summary = l.flush()
+		}
+		l.mu.Unlock()
+		l.logSummary(summary)
+	})
+	l.timer = timer
+	l.mu.Unlock()
+
+	// log without holding l.mu, so the wrapped logger may log to l
+	l.logSummary(summary)
+	l.logger.Log(level, kvs...)
+}
+
+// flush returns summary of the repetition and resets it, l.mu must be held.
+func (l *dedupLogger) flush() Record {
+	if l.timer == nil {
+		return Record{}
+	}
+	l.timer.Stop()
+	l.timer = nil
+
+	if l.repeat == 0 {
+		return Record{}
+	}
+	kvs := []interface{}{
+		DefaultMsgKey, fmt.Sprintf("last message repeated %d times", l.repeat),
+		"repeated", l.repeat,
+		"first", l.first.Format(DefaultTimestampFormat),
+		"last", l.last.Format(DefaultTimestampFormat),
+	}
+	if l.msg != nil {
+		kvs = append(kvs, "repeated_msg", l.msg)
+	}
+	l.repeat = 0
+	return Record{Level: l.level, KVs: kvs}
+}
+
+// logSummary logs the summary returned by flush if there is one.
+func (l *dedupLogger) logSummary(summary Record) {
+	if summary.KVs != nil {
+		l.logger.Log(summary.Level, summary.KVs...)
+	}
+}
+
+// Sync logs summary of the pending repetition and flushes buffered logs of
+// the wrapped logger.
+func (l *dedupLogger) Sync() error {
+	l.mu.Lock()
+	summary := l.flush()
+	l.mu.Unlock()
+	l.logSummary(summary)
+	return Sync(l.logger)
+}
+
+var _ Logger = (*dedupLogger)(nil)
+
+// Dedup creates a logger that collapses consecutive identical logs into
+// the first one, a "last message repeated N times" summary with the first
+// and last time of the repetition is logged when a different log arrives
+// or the window elapses.
+//
+// Logs are identical if they have the same level and message, or the same
+// level and kvs if DedupKVs is set or logs have no message. Lazy values are
+// not evaluated to compare logs, so logs only differ in them are identical.
+// Handlers which add timestamp or caller should be applied to the wrapped
+// logger, so that they do not make logs different, the caller is still the
+// caller of Dedup.
+func Dedup(logger Logger, opts ...DedupOption) Logger {
+	l := &dedupLogger{
+		logger: logger,
+		window: DefaultDedupWindow,
+		now:    time.Now,
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
@@ -0,0 +1,165 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func fixDedupNow(l Logger) *time.Time {
+	now := time.Date(2022, 10, 28, 16, 37, 50, 0, time.UTC)
+	l.(*dedupLogger).now = func() time.Time {
+		return now
+	}
+	return &now
+}
+
+// Test that Dedup properly collapses repeated logs.
+func TestDedup(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := Dedup(NewStdLogger(buf), DedupWindow(time.Hour))
+	now := fixDedupNow(l)
+	l.Log(LevelInfo, DefaultMsgKey, "retry", "n", 1)
+	*now = now.Add(time.Second)
+	l.Log(LevelInfo, DefaultMsgKey, "retry", "n", 2)
+	*now = now.Add(time.Second)
+	l.Log(LevelInfo, DefaultMsgKey, "retry", "n", 3)
+	l.Log(LevelWarn, DefaultMsgKey, "retry")
+	l.Log(LevelWarn, DefaultMsgKey, "done")
+	l.Log(LevelWarn, DefaultMsgKey, "done")
+	if err := Sync(l); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	l.Log(LevelWarn, DefaultMsgKey, "done")
+
+	want := `INFO, "msg": "retry", "n": "1"
+INFO, "msg": "last message repeated 2 times", "repeated": "2", "first": "2022-10-28T16:37:50.000Z", "last": "2022-10-28T16:37:52.000Z", "repeated_msg": "retry"
+WARN, "msg": "retry"
+WARN, "msg": "done"
+WARN, "msg": "last message repeated 1 times", "repeated": "1", "first": "2022-10-28T16:37:52.000Z", "last": "2022-10-28T16:37:52.000Z", "repeated_msg": "done"
+WARN, "msg": "done"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that Dedup properly compares kvs.
+func TestDedupKVs(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := Dedup(NewStdLogger(buf), DedupWindow(time.Hour), DedupKVs(true))
+	fixDedupNow(l)
+	l.Log(LevelInfo, DefaultMsgKey, "retry", "n", 1)
+	l.Log(LevelInfo, DefaultMsgKey, "retry", "n", 1)
+	l.Log(LevelInfo, DefaultMsgKey, "retry", "n", 2)
+	_ = Sync(l)
+
+	want := `INFO, "msg": "retry", "n": "1"
+INFO, "msg": "last message repeated 1 times", "repeated": "1", "first": "2022-10-28T16:37:50.000Z", "last": "2022-10-28T16:37:50.000Z", "repeated_msg": "retry"
+INFO, "msg": "retry", "n": "2"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that Dedup compares all kvs of logs without message.
+func TestDedupWithoutMsg(t *testing.T) {
+	buf := &bytes.Buffer{}
+	l := Dedup(NewStdLogger(buf), DedupWindow(time.Hour))
+	fixDedupNow(l)
+	l.Log(LevelInfo, "user", "alice")
+	l.Log(LevelInfo, "user", "bob")
+	l.Log(LevelInfo, "user", "bob")
+	_ = Sync(l)
+
+	want := `INFO, "user": "alice"
+INFO, "user": "bob"
+INFO, "msg": "last message repeated 1 times", "repeated": "1", "first": "2022-10-28T16:37:50.000Z", "last": "2022-10-28T16:37:50.000Z"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that caller handler of the wrapped logger reports the caller of Dedup.
+func TestDedupCaller(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := Dedup(WithHandler(NewStdLogger(buf), HandlerDefaultCaller))
+	l.Log(LevelInfo, DefaultMsgKey, "m1")
+
+	if got, want := buf.String(), `INFO, "msg": "m1", "caller": "dedup_test.go:91"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that Dedup properly logs summary when the window elapses.
+func TestDedupWindow(t *testing.T) {
+	buf := &syncBuffer{}
+	l := Dedup(NewStdLogger(buf), DedupWindow(50*time.Millisecond))
+	now := fixDedupNow(l)
+	l.Log(LevelInfo, DefaultMsgKey, "m")
+	l.Log(LevelInfo, DefaultMsgKey, "m")
+
+	want := `INFO, "msg": "m"
+INFO, "msg": "last message repeated 1 times", "repeated": "1", "first": "2022-10-28T16:37:50.000Z", "last": "2022-10-28T16:37:50.000Z", "repeated_msg": "m"
+`
+	deadline := time.Now().Add(2 * time.Second)
+	for buf.String() != want && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := buf.String(); got != want {
+		t.Fatalf("buf.String() = %q want %q", got, want)
+	}
+
+	// the same log after window starts a new repetition
+	*now = now.Add(time.Minute)
+	l.Log(LevelInfo, DefaultMsgKey, "m")
+	_ = Sync(l)
+	if got, want := buf.String(), want+"INFO, \"msg\": \"m\"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that Dedup does not evaluate Lazy values of logs discarded by the wrapped logger.
+func TestDedupLazy(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	dump := Lazy(func() interface{} {
+		calls++
+		return "dump"
+	})
+	buf := &bytes.Buffer{}
+	l := Dedup(WithFilter(NewStdLogger(buf), FilterLevel(LevelError)), DedupKVs(true))
+	l.Log(LevelDebug, DefaultMsgKey, "m", "dump", dump)
+	l.Log(LevelDebug, DefaultMsgKey, "m", "dump", dump)
+	if err := Sync(l); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	if calls != 0 || buf.Len() != 0 {
+		t.Errorf("Lazy evaluated %d times, buf.String() = %q", calls, buf.String())
+	}
+}
+
+// Test that the wrapped logger can log to Dedup.
+func TestDedupReentrant(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	var l Logger
+	l = Dedup(loggerFunc(func(level Level, kvs ...interface{}) {
+		if level == LevelError {
+			l.Log(LevelInfo, DefaultMsgKey, "reported")
+		}
+		NewStdLogger(buf).Log(level, kvs...)
+	}))
+	l.Log(LevelError, DefaultMsgKey, "failed")
+
+	want := "INFO, \"msg\": \"reported\"\nERROR, \"msg\": \"failed\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
@@ -0,0 +1,16 @@
+// Package expvarlog publishes golog metrics as expvar variables.
+package expvarlog
+
+import (
+	"expvar"
+
+	"github.com/kibaamor/golog"
+)
+
+// Publish publishes the snapshot of m as an expvar variable with name,
+// it panics if the name is already registered as expvar.Publish does.
+func Publish(name string, m *golog.Metrics) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return m.Snapshot()
+	}))
+}
@@ -0,0 +1,42 @@
+package expvarlog
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kibaamor/golog"
+)
+
+// published counts names published by tests, names of expvar can not be reused
+// when tests run more than once.
+var published int32
+
+// Test that Publish properly publishes metrics to expvar.
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	name := fmt.Sprintf("golog_test_%d", atomic.AddInt32(&published, 1))
+	m := golog.NewMetrics()
+	Publish(name, m)
+	m.Logger(golog.Discard).Log(golog.LevelWarn)
+
+	rec := httptest.NewRecorder()
+	expvar.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/debug/vars", nil))
+
+	var vars map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &vars); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	var snapshot golog.MetricsSnapshot
+	if err := json.Unmarshal(vars[name], &snapshot); err != nil {
+		t.Fatalf("json.Unmarshal(%s) error: %v", name, err)
+	}
+	if got, want := snapshot.Records, map[string]uint64{"WARN": 1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Records = %v want %v", got, want)
+	}
+}
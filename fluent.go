@@ -0,0 +1,150 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+type fluentLogger struct {
+	mu  sync.Mutex
+	w   io.Writer
+	tag string
+	buf bytes.Buffer
+	now func() time.Time
+}
+
+// NewFluentLogger new a logger which writes each log as a Fluent Forward
+// protocol message [tag, time, record] encoded in MessagePack.
+// It can be used with NetFormat to send logs to Fluentd or Fluent Bit.
+func NewFluentLogger(w io.Writer, tag string) Logger {
+	return &fluentLogger{w: w, tag: tag, now: time.Now}
+}
+
+// Log write the kv pairs log.
+func (l *fluentLogger) Log(level Level, kvs ...interface{}) {
+	if len(kvs) == 0 {
+		return
+	}
+
+	kvs = normalizeKVs(DefaultKeyPolicy, kvs)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.buf.Reset()
+	msgpackArrayHeader(&l.buf, 3)
+	msgpackString(&l.buf, l.tag)
+	msgpackValue(&l.buf, l.now().Unix())
+	msgpackMapHeader(&l.buf, len(kvs)/2+1)
+	msgpackString(&l.buf, DefaultLevelKey)
+	msgpackString(&l.buf, level.String())
+	for i := 0; i < len(kvs); i += 2 {
+		msgpackString(&l.buf, fmt.Sprint(kvs[i]))
+		msgpackValue(&l.buf, kvs[i+1])
+	}
+	_, _ = l.w.Write(l.buf.Bytes())
+}
+
+func msgpackHeader(buf *bytes.Buffer, n int, fix, b16, b32 byte, fixMax int) {
+	switch {
+	case n <= fixMax:
+		_ = buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		_ = buf.WriteByte(b16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		_ = buf.WriteByte(b32)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+func msgpackArrayHeader(buf *bytes.Buffer, n int) {
+	msgpackHeader(buf, n, 0x90, 0xdc, 0xdd, 15)
+}
+
+func msgpackMapHeader(buf *bytes.Buffer, n int) {
+	msgpackHeader(buf, n, 0x80, 0xde, 0xdf, 15)
+}
+
+func msgpackString(buf *bytes.Buffer, s string) {
+	if len(s) > 31 && len(s) <= math.MaxUint8 {
+		_ = buf.WriteByte(0xd9)
+		_ = buf.WriteByte(byte(len(s)))
+	} else {
+		msgpackHeader(buf, len(s), 0xa0, 0xda, 0xdb, 31)
+	}
+	_, _ = buf.WriteString(s)
+}
+
+func msgpackInt(buf *bytes.Buffer, i int64) {
+	if i >= -32 && i <= math.MaxInt8 {
+		_ = buf.WriteByte(byte(i))
+		return
+	}
+	_ = buf.WriteByte(0xd3)
+	_ = binary.Write(buf, binary.BigEndian, i)
+}
+
+func msgpackValue(buf *bytes.Buffer, v interface{}) {
+	if msgpackNumber(buf, v) {
+		return
+	}
+	switch v := v.(type) {
+	case nil:
+		_ = buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			_ = buf.WriteByte(0xc3)
+		} else {
+			_ = buf.WriteByte(0xc2)
+		}
+	case string:
+		msgpackString(buf, v)
+	case error:
+		msgpackString(buf, v.Error())
+	default:
+		msgpackString(buf, fmt.Sprint(v))
+	}
+}
+
+// msgpackNumber write v if it is a number, reports whether v is written.
+func msgpackNumber(buf *bytes.Buffer, v interface{}) bool {
+	switch v := v.(type) {
+	case int:
+		msgpackInt(buf, int64(v))
+	case int8:
+		msgpackInt(buf, int64(v))
+	case int16:
+		msgpackInt(buf, int64(v))
+	case int32:
+		msgpackInt(buf, int64(v))
+	case int64:
+		msgpackInt(buf, v)
+	case uint8:
+		msgpackInt(buf, int64(v))
+	case uint16:
+		msgpackInt(buf, int64(v))
+	case uint32:
+		msgpackInt(buf, int64(v))
+	case uint:
+		_ = buf.WriteByte(0xcf)
+		_ = binary.Write(buf, binary.BigEndian, uint64(v))
+	case uint64:
+		_ = buf.WriteByte(0xcf)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	case float32:
+		_ = buf.WriteByte(0xcb)
+		_ = binary.Write(buf, binary.BigEndian, float64(v))
+	case float64:
+		_ = buf.WriteByte(0xcb)
+		_ = binary.Write(buf, binary.BigEndian, v)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,30 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// Test that fluentLogger properly encodes logs as Fluent Forward messages.
+func TestFluentLogger(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewFluentLogger(&buf, "app")
+	log.(*fluentLogger).now = func() time.Time {
+		return time.Unix(0x01020304, 0)
+	}
+	log.Log(LevelInfo, "k1", 1, "k2", true)
+
+	want := []byte{
+		0x93,                // array of 3
+		0xa3, 'a', 'p', 'p', // tag
+		0xd3, 0, 0, 0, 0, 0x01, 0x02, 0x03, 0x04, // time
+		0x83, // map of 3
+		0xa5, 'l', 'e', 'v', 'e', 'l', 0xa4, 'I', 'N', 'F', 'O',
+		0xa2, 'k', '1', 0x01,
+		0xa2, 'k', '2', 0xc3,
+	}
+	if got := buf.Bytes(); !bytes.Equal(got, want) {
+		t.Errorf("buf.Bytes() = %x want %x", got, want)
+	}
+}
@@ -0,0 +1,200 @@
+// Package gologtest provides loggers for testing code which uses golog.
+package gologtest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kibaamor/golog"
+)
+
+// Field is a key value pair of Entry.
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// Entry is a recorded log.
+type Entry struct {
+	Level  golog.Level
+	Fields []Field
+	Time   time.Time
+}
+
+// Value returns the value of the first field with key.
+func (e *Entry) Value(key string) (interface{}, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f.Value, true
+		}
+	}
+	return nil, false
+}
+
+// Map returns the fields as a map, the last field wins if keys are duplicated.
+func (e *Entry) Map() map[string]interface{} {
+	m := make(map[string]interface{}, len(e.Fields))
+	for _, f := range e.Fields {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+// Msg returns the value of golog.DefaultMsgKey as string.
+func (e *Entry) Msg() string {
+	if v, ok := e.Value(golog.DefaultMsgKey); ok {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+// Entries is a list of recorded logs.
+type Entries []Entry
+
+// FilterByLevel returns entries with level.
+func (es Entries) FilterByLevel(level golog.Level) Entries {
+	return es.Filter(func(e *Entry) bool {
+		return e.Level == level
+	})
+}
+
+// FilterByField returns entries with field key equal to value.
+func (es Entries) FilterByField(key string, value interface{}) Entries {
+	return es.Filter(func(e *Entry) bool {
+		v, ok := e.Value(key)
+		return ok && fmt.Sprint(v) == fmt.Sprint(value)
+	})
+}
+
+// FilterByMsg returns entries with message contains substr.
+func (es Entries) FilterByMsg(substr string) Entries {
+	return es.Filter(func(e *Entry) bool {
+		return strings.Contains(e.Msg(), substr)
+	})
+}
+
+// Filter returns entries satisfying fn.
+func (es Entries) Filter(fn func(e *Entry) bool) Entries {
+	var filtered Entries
+	for i := range es {
+		if fn(&es[i]) {
+			filtered = append(filtered, es[i])
+		}
+	}
+	return filtered
+}
+
+// Len returns the number of entries.
+func (es Entries) Len() int {
+	return len(es)
+}
+
+// Recorder is a Logger which records logs in memory.
+// It is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entries Entries
+	nowFunc func() time.Time
+}
+
+var _ golog.Logger = (*Recorder)(nil)
+
+// NewRecorder new a recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{nowFunc: time.Now}
+}
+
+// Log record the kv pairs log.
+func (r *Recorder) Log(level golog.Level, kvs ...interface{}) {
+	e := Entry{
+		Level:  level,
+		Fields: make([]Field, 0, (len(kvs)+1)/2),
+		Time:   r.nowFunc(),
+	}
+	// pair kvs, expand groups and evaluate lazy values as encoders do
+	kvs = golog.HandlerKeyPolicy(golog.KeyStringify)(level, kvs)
+	for i := 0; i < len(kvs); i += 2 {
+		e.Fields = append(e.Fields, Field{Key: fmt.Sprint(kvs[i]), Value: kvs[i+1]})
+	}
+
+	r.mu.Lock()
+	r.entries = append(r.entries, e)
+	r.mu.Unlock()
+}
+
+// All returns a copy of recorded logs.
+func (r *Recorder) All() Entries {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(Entries(nil), r.entries...)
+}
+
+// TakeAll returns recorded logs and clear the recorder.
+func (r *Recorder) TakeAll() Entries {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := r.entries
+	r.entries = nil
+	return entries
+}
+
+// Len returns the number of recorded logs.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// FilterByLevel returns recorded logs with level.
+func (r *Recorder) FilterByLevel(level golog.Level) Entries {
+	return r.All().FilterByLevel(level)
+}
+
+// FilterByField returns recorded logs with field key equal to value.
+func (r *Recorder) FilterByField(key string, value interface{}) Entries {
+	return r.All().FilterByField(key, value)
+}
+
+// Contains reports whether any recorded log has message contains substr
+// and all of the kv pairs.
+func (r *Recorder) Contains(substr string, kvs ...interface{}) bool {
+	es := r.All().FilterByMsg(substr)
+	for i := 0; i+1 < len(kvs); i += 2 {
+		es = es.FilterByField(fmt.Sprint(kvs[i]), kvs[i+1])
+	}
+	return len(es) > 0
+}
+
+type testLogger struct {
+	tb testing.TB
+}
+
+// NewTestLogger new a logger which writes logs through tb.Log,
+// so logs are attached to the test and printed only if it fails or -v is set.
+func NewTestLogger(tb testing.TB) golog.Logger {
+	return &testLogger{tb: tb}
+}
+
+// Log write the kv pairs log.
+func (l *testLogger) Log(level golog.Level, kvs ...interface{}) {
+	l.tb.Helper()
+
+	var buf bytes.Buffer
+	golog.NewTermLogger(&buf, false).Log(level, kvs...)
+	if buf.Len() > 0 {
+		l.tb.Log(strings.TrimSuffix(buf.String(), "\n"))
+	}
+}
+
+// Validate wraps logger with golog.ValidateLogger which reports misuse of
+// logger as errors of tb.
+func Validate(tb testing.TB, logger golog.Logger) golog.Logger {
+	return golog.ValidateLogger(logger, func(problem string) {
+		tb.Helper()
+		tb.Errorf("golog: %s", problem)
+	})
+}
@@ -0,0 +1,125 @@
+package gologtest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kibaamor/golog"
+)
+
+// Test that Recorder properly records logs.
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder()
+	helper := golog.NewHelper(r)
+	helper.Info("hello")
+	r.Log(golog.LevelError, "k1", "v1", "k2")
+
+	all := r.All()
+	if got, want := len(all), 2; got != want {
+		t.Fatalf("len(all) = %d want %d", got, want)
+	}
+	if got, want := all[0].Msg(), "hello"; got != want {
+		t.Errorf("all[0].Msg() = %q want %q", got, want)
+	}
+	want := []Field{{"k1", "v1"}, {"k2", "KEY VALUES UNPAIRED"}}
+	if got := all[1].Fields; fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("all[1].Fields = %v want %v", got, want)
+	}
+}
+
+// Test that Recorder query helpers properly filter logs.
+func TestRecorderQuery(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder()
+	r.Log(golog.LevelInfo, golog.DefaultMsgKey, "request done", "status", 200)
+	r.Log(golog.LevelError, golog.DefaultMsgKey, "request failed", "status", 500)
+	r.Log(golog.LevelError, golog.DefaultMsgKey, "db failed")
+
+	if got, want := r.FilterByLevel(golog.LevelError).Len(), 2; got != want {
+		t.Errorf("FilterByLevel().Len() = %d want %d", got, want)
+	}
+	if got, want := r.FilterByField("status", 500).Len(), 1; got != want {
+		t.Errorf("FilterByField().Len() = %d want %d", got, want)
+	}
+	if !r.Contains("request", "status", 200) {
+		t.Errorf("Contains() = false want true")
+	}
+	if r.Contains("db", "status", 500) {
+		t.Errorf("Contains() = true want false")
+	}
+	if got, want := len(r.TakeAll()), 3; got != want {
+		t.Errorf("len(TakeAll()) = %d want %d", got, want)
+	}
+	if got, want := r.Len(), 0; got != want {
+		t.Errorf("Len() = %d want %d", got, want)
+	}
+}
+
+// Test that Recorder is safe for concurrent use.
+func TestRecorderConcurrent(t *testing.T) {
+	t.Parallel()
+
+	r := NewRecorder()
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.Log(golog.LevelInfo, "i", i)
+		}(i)
+	}
+	wg.Wait()
+
+	if got, want := r.Len(), 10; got != want {
+		t.Errorf("Len() = %d want %d", got, want)
+	}
+}
+
+type fakeTB struct {
+	testing.TB
+	logs   []string
+	errors []string
+}
+
+func (tb *fakeTB) Helper() {}
+
+func (tb *fakeTB) Log(args ...interface{}) {
+	tb.logs = append(tb.logs, fmt.Sprint(args...))
+}
+
+func (tb *fakeTB) Errorf(format string, args ...interface{}) {
+	tb.errors = append(tb.errors, fmt.Sprintf(format, args...))
+}
+
+// Test that NewTestLogger properly writes logs through testing.TB.
+func TestNewTestLogger(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{TB: t}
+	logger := NewTestLogger(tb)
+	logger.Log(golog.LevelInfo, golog.DefaultMsgKey, "hello", "k1", "v1")
+
+	if got, want := fmt.Sprint(tb.logs), "[[INFO] hello k1:v1]"; got != want {
+		t.Errorf("tb.logs = %q want %q", got, want)
+	}
+}
+
+// Test that Validate properly reports misuse as test errors.
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	tb := &fakeTB{TB: t}
+	r := NewRecorder()
+	Validate(tb, r).Log(golog.LevelInfo, "k1")
+
+	if got, want := fmt.Sprint(tb.errors), "[golog: gologtest_test.go:117: key k1 without value]"; got != want {
+		t.Errorf("tb.errors = %q want %q", got, want)
+	}
+	if got := r.Len(); got != 1 {
+		t.Errorf("r.Len() = %d want 1", got)
+	}
+}
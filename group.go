@@ -0,0 +1,157 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// DefaultGroupSeparator is default separator between group name and keys
+// of loggers which flatten groups.
+var DefaultGroupSeparator = "."
+
+// GroupValue is a group of kv pairs created by Group.
+type GroupValue struct {
+	Name string
+	KVs  []interface{}
+}
+
+// Group creates a group of kv pairs, it takes the place of a kv pair in kvs:
+//
+//	logger.Log(golog.LevelInfo, "msg", "done", golog.Group("http", "method", m, "status", s))
+//
+// JSON loggers render groups as nested objects, others flatten keys of groups
+// with separator, e.g. "http.method". Groups without name are inlined and
+// groups without kvs are omitted.
+func Group(name string, kvs ...interface{}) GroupValue {
+	return GroupValue{Name: name, KVs: kvs}
+}
+
+// String formats the group as "{k1=v1 k2=v2}".
+func (g GroupValue) String() string {
+	kvs := normalizeKVs(KeyStringify, g.KVs)
+	var sb strings.Builder
+	_ = sb.WriteByte('{')
+	for i := 0; i < len(kvs); i += 2 {
+		if i > 0 {
+			_ = sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%v=%v", kvs[i], kvs[i+1])
+	}
+	_ = sb.WriteByte('}')
+	return sb.String()
+}
+
+// MarshalJSON encodes the group as a JSON object.
+func (g GroupValue) MarshalJSON() ([]byte, error) {
+	kvs := normalizeKVs(DefaultKeyPolicy, g.KVs)
+	var buf bytes.Buffer
+	_ = buf.WriteByte('{')
+	for i := 0; i < len(kvs); i += 2 {
+		if i > 0 {
+			_ = buf.WriteByte(',')
+		}
+		writeJSONString(&buf, fmt.Sprint(kvs[i]))
+		_ = buf.WriteByte(':')
+		writeJSONValue(&buf, kvs[i+1])
+	}
+	_ = buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// expandGroups replaces groups in place of kv pairs with pairs of their name
+// and themselves, groups without name are inlined.
+func expandGroups(kvs []interface{}) []interface{} {
+	found := false
+	for i := 0; i < len(kvs); i += 2 {
+		if _, ok := kvs[i].(GroupValue); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return kvs
+	}
+
+	out := make([]interface{}, 0, len(kvs)+1)
+	for i := 0; i < len(kvs); {
+		g, ok := kvs[i].(GroupValue)
+		switch {
+		case !ok:
+			if i+1 == len(kvs) {
+				out = append(out, kvs[i])
+				i++
+				continue
+			}
+			out = append(out, kvs[i], kvs[i+1])
+			i += 2
+		case len(g.KVs) == 0:
+			i++
+		case g.Name == "":
+			out = append(out, expandGroups(g.KVs)...)
+			i++
+		default:
+			out = append(out, g.Name, g)
+			i++
+		}
+	}
+	return out
+}
+
+// flattenGroups replaces kv pairs with group values by kv pairs of the groups,
+// keys are prefixed with key of the group and sep. kvs must be paired.
+func flattenGroups(sep string, kvs []interface{}) []interface{} {
+	found := false
+	for i := 1; i < len(kvs); i += 2 {
+		if _, ok := kvs[i].(GroupValue); ok {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return kvs
+	}
+
+	out := make([]interface{}, 0, len(kvs))
+	for i := 0; i < len(kvs); i += 2 {
+		g, ok := kvs[i+1].(GroupValue)
+		if !ok {
+			out = append(out, kvs[i], kvs[i+1])
+			continue
+		}
+		prefix := fmt.Sprint(kvs[i]) + sep
+		sub := flattenGroups(sep, normalizeKVs(DefaultKeyPolicy, g.KVs))
+		for j := 0; j < len(sub); j += 2 {
+			out = append(out, prefix+fmt.Sprint(sub[j]), sub[j+1])
+		}
+	}
+	return out
+}
+
+type groupLogger struct {
+	logger Logger
+	name   string
+}
+
+func (l *groupLogger) Log(level Level, kvs ...interface{}) {
+	msg, others := splitMsg(pairKVs(DefaultKeyPolicy, kvs))
+	if msg == nil {
+		l.logger.Log(level, Group(l.name, others...))
+		return
+	}
+	l.logger.Log(level, DefaultMsgKey, msg, Group(l.name, others...))
+}
+
+// Sync flushes buffered logs of the wrapped logger.
+func (l *groupLogger) Sync() error {
+	return Sync(l.logger)
+}
+
+var _ Logger = (*groupLogger)(nil)
+
+// WithGroup creates a logger that puts kv pairs of logs, except message,
+// into a group with name. Kv pairs added by the wrapped logger, such as
+// timestamp and caller, are not grouped.
+func WithGroup(logger Logger, name string) Logger {
+	return &groupLogger{logger: logger, name: name}
+}
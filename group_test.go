@@ -0,0 +1,105 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Test that loggers properly render groups.
+func TestGroup(t *testing.T) {
+	t.Parallel()
+
+	kvs := []interface{}{
+		DefaultMsgKey, "done",
+		Group("http", "method", "GET", Group("resp", "status", 200)),
+		Group("empty"),
+		Group("", "k1", 1),
+	}
+	tests := []struct {
+		name      string
+		newLogger func(w *bytes.Buffer) Logger
+		want      string
+	}{
+		{
+			name:      "JSON",
+			newLogger: func(w *bytes.Buffer) Logger { return NewJSONLogger(w) },
+			want:      `{"level":"INFO","msg":"done","http":{"method":"GET","resp":{"status":200}},"k1":1}` + "\n",
+		},
+		{
+			name:      "Std",
+			newLogger: func(w *bytes.Buffer) Logger { return NewStdLogger(w) },
+			want:      `INFO, "msg": "done", "http.method": "GET", "http.resp.status": "200", "k1": "1"` + "\n",
+		},
+		{
+			name:      "Term with separator",
+			newLogger: func(w *bytes.Buffer) Logger { return NewTermLogger(w, false, TermGroupSeparator("_")) },
+			want:      "[INFO] done http_method:GET http_resp_status:200 k1:1\n",
+		},
+		{
+			name:      "Logfmt",
+			newLogger: func(w *bytes.Buffer) Logger { return NewLogfmtLogger(w) },
+			want:      "level=INFO msg=done http.method=GET http.resp.status=200 k1=1\n",
+		},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		tt.newLogger(&buf).Log(LevelInfo, kvs...)
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: buf.String() = %q want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Test that GroupValue.String properly formats group.
+func TestGroupValueString(t *testing.T) {
+	t.Parallel()
+
+	g := Group("http", "method", "GET", Group("resp", "status", 200))
+	if got, want := g.String(), "{method=GET resp={status=200}}"; got != want {
+		t.Errorf("g.String() = %q want %q", got, want)
+	}
+}
+
+// Test that WithGroup properly groups kv pairs except message.
+func TestWithGroup(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	root := WithHandler(NewJSONLogger(&buf), HandlerFields("app", "a"))
+	db := WithGroup(WithGroup(root, "db"), "pool")
+	db.Log(LevelInfo, DefaultMsgKey, "acquired", "conns", 3)
+	db.Log(LevelInfo, "conns", 4)
+
+	want := `{"level":"INFO","msg":"acquired","db":{"pool":{"conns":3}},"app":"a"}` + "\n" +
+		`{"level":"INFO","db":{"pool":{"conns":4}},"app":"a"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that caller handler of the wrapped logger reports the caller of WithGroup logger.
+func TestWithGroupCaller(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := WithGroup(WithHandler(NewStdLogger(&buf), HandlerDefaultCaller), "g")
+	l.Log(LevelInfo, "k1", "v1")
+
+	if got, want := buf.String(), `INFO, "g.k1": "v1", "caller": "group_test.go:86"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that decorated logger properly expands groups before filters.
+func TestGroupWithFilter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := WithFilter(NewStdLogger(&buf), func(level Level, kvs []interface{}) bool {
+		return len(kvs) != 4 || kvs[2] != "k1"
+	})
+	l.Log(LevelInfo, Group("g", "k", "v"), "k1", "v1")
+	if got, want := buf.String(), `INFO, "g.k": "v", "k1": "v1"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
@@ -0,0 +1,287 @@
+// Package grpclog provides gRPC interceptors which log calls with golog,
+// and an adapter which routes grpc-go internal logs to golog.
+package grpclog
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/kibaamor/golog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// DefaultMsg is default message of call logs.
+	DefaultMsg = "grpc call"
+	// DefaultPayloadMsg is default message of payload logs.
+	DefaultPayloadMsg = "grpc payload"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx which carries logger.
+func NewContext(ctx context.Context, logger golog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger helper carried by ctx, it logs
+// nothing if ctx carries no logger.
+func FromContext(ctx context.Context) *golog.Helper {
+	if logger, ok := ctx.Value(contextKey{}).(golog.Logger); ok {
+		return golog.NewHelper(logger)
+	}
+	return golog.NewHelper(golog.Discard)
+}
+
+// DefaultCodeLevel convert gRPC code to log level, client errors are
+// logged at WARN and server errors are logged at ERROR.
+func DefaultCodeLevel(code codes.Code) golog.Level {
+	switch code {
+	case codes.OK:
+		return golog.LevelInfo
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.ResourceExhausted,
+		codes.FailedPrecondition, codes.Aborted, codes.OutOfRange:
+		return golog.LevelWarn
+	default:
+		return golog.LevelError
+	}
+}
+
+// Option is interceptor option.
+type Option func(o *options)
+
+// CodeLevel set the function convert gRPC code to log level, default is DefaultCodeLevel.
+func CodeLevel(fn func(code codes.Code) golog.Level) Option {
+	return func(o *options) {
+		o.codeLevel = fn
+	}
+}
+
+// Payloads log each sent and received message at DEBUG, messages are
+// passed to redact before being logged, so sensitive fields can be removed.
+func Payloads(redact func(msg interface{}) interface{}) Option {
+	return func(o *options) {
+		o.redact = redact
+	}
+}
+
+type options struct {
+	codeLevel func(code codes.Code) golog.Level
+	redact    func(msg interface{}) interface{}
+	now       func() time.Time
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{codeLevel: DefaultCodeLevel, now: time.Now}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// call records a gRPC call in progress, messages of streams may be sent
+// and received in different goroutines.
+type call struct {
+	o      *options
+	helper *golog.Helper
+	start  time.Time
+
+	mu       sync.Mutex
+	sent     int
+	received int
+}
+
+func newCall(o *options, logger golog.Logger, kind, method string) (*call, golog.Logger) {
+	logger = golog.WithHandler(logger, golog.HandlerFields("kind", kind, "method", method))
+	return &call{o: o, helper: golog.NewHelper(logger), start: o.now()}, logger
+}
+
+func (c *call) payload(direction string, msg interface{}) {
+	if c.o.redact == nil {
+		return
+	}
+	c.helper.Log(golog.LevelDebug,
+		golog.DefaultMsgKey, DefaultPayloadMsg,
+		"direction", direction,
+		"payload", c.o.redact(msg),
+	)
+}
+
+func (c *call) send(msg interface{}) {
+	c.mu.Lock()
+	c.sent++
+	c.mu.Unlock()
+	c.payload("send", msg)
+}
+
+func (c *call) recv(msg interface{}) {
+	c.mu.Lock()
+	c.received++
+	c.mu.Unlock()
+	c.payload("recv", msg)
+}
+
+func (c *call) done(p *peer.Peer, err error) {
+	c.mu.Lock()
+	sent, received := c.sent, c.received
+	c.mu.Unlock()
+
+	code := status.Code(err)
+	kvs := []interface{}{
+		golog.DefaultMsgKey, DefaultMsg,
+		"code", code.String(),
+		"duration", c.o.now().Sub(c.start),
+		"sent", sent,
+		"received", received,
+	}
+	if p != nil && p.Addr != nil {
+		kvs = append(kvs, "peer", p.Addr.String())
+	}
+	if err != nil {
+		kvs = append(kvs, "error", status.Convert(err).Message())
+	}
+	c.helper.Log(c.o.codeLevel(code), kvs...)
+}
+
+// UnaryServerInterceptor returns a server interceptor which logs unary calls.
+// The logger decorated with call information is carried by handler context.
+func UnaryServerInterceptor(logger golog.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler) (interface{}, error) {
+		c, l := newCall(o, logger, "server", info.FullMethod)
+		p, _ := peer.FromContext(ctx)
+		c.recv(req)
+		resp, err := handler(NewContext(ctx, l), req)
+		if err == nil {
+			c.send(resp)
+		}
+		c.done(p, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor which logs stream calls.
+// The logger decorated with call information is carried by stream context.
+func StreamServerInterceptor(logger golog.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	o := newOptions(opts)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c, l := newCall(o, logger, "server", info.FullMethod)
+		p, _ := peer.FromContext(ss.Context())
+		err := handler(srv, &serverStream{ServerStream: ss, call: c, ctx: NewContext(ss.Context(), l)})
+		c.done(p, err)
+		return err
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	call *call
+	ctx  context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *serverStream) SendMsg(m interface{}) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.call.send(m)
+	}
+	return err
+}
+
+func (s *serverStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.call.recv(m)
+	}
+	return err
+}
+
+// clientLogger returns the logger carried by ctx, or logger if there is none.
+func clientLogger(ctx context.Context, logger golog.Logger) golog.Logger {
+	if l, ok := ctx.Value(contextKey{}).(golog.Logger); ok {
+		return l
+	}
+	return logger
+}
+
+// UnaryClientInterceptor returns a client interceptor which logs unary calls.
+// The logger carried by call context is preferred to logger.
+func UnaryClientInterceptor(logger golog.Logger, opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		c, _ := newCall(o, clientLogger(ctx, logger), "client", method)
+		p := &peer.Peer{}
+		c.send(req)
+		err := invoker(ctx, method, req, reply, cc, append(callOpts, grpc.Peer(p))...)
+		if err == nil {
+			c.recv(reply)
+		}
+		c.done(p, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor which logs stream calls
+// when the stream ends. The logger carried by call context is preferred to logger.
+func StreamClientInterceptor(logger golog.Logger, opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, callOpts ...grpc.CallOption) (grpc.ClientStream, error) {
+		c, _ := newCall(o, clientLogger(ctx, logger), "client", method)
+		p := &peer.Peer{}
+		cs, err := streamer(ctx, desc, cc, method, append(callOpts, grpc.Peer(p))...)
+		if err != nil {
+			c.done(p, err)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, call: c, peer: p}, nil
+	}
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	call *call
+	peer *peer.Peer
+	once sync.Once
+}
+
+// finish logs the call once, SendMsg and RecvMsg may both end the stream.
+func (s *clientStream) finish(err error) {
+	s.once.Do(func() {
+		if err == io.EOF { //nolint:errorlint
+			err = nil
+		}
+		s.call.done(s.peer, err)
+	})
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	err := s.ClientStream.SendMsg(m)
+	if err == nil {
+		s.call.send(m)
+	} else if err != io.EOF { //nolint:errorlint
+		s.finish(err)
+	}
+	return err
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil {
+		s.call.recv(m)
+		return nil
+	}
+	s.finish(err)
+	return err
+}
@@ -0,0 +1,236 @@
+package grpclog
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kibaamor/golog"
+	"github.com/kibaamor/golog/gologtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type healthServer struct {
+	*health.Server
+}
+
+func (s *healthServer) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	FromContext(ctx).Info("checking")
+	return s.Server.Check(ctx, req)
+}
+
+// newConn starts a server over bufconn and returns a client connection to it.
+func newConn(t *testing.T, server, client golog.Logger, opts ...Option) *grpc.ClientConn {
+	t.Helper()
+
+	ln := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(server, opts...)),
+		grpc.StreamInterceptor(StreamServerInterceptor(server, opts...)),
+	)
+	healthpb.RegisterHealthServer(srv, &healthServer{Server: health.NewServer()})
+	go func() {
+		_ = srv.Serve(ln)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(client, opts...)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(client, opts...)),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn
+}
+
+// waitEntries waits until recorder has n entries, since server logs after response is sent.
+func waitEntries(t *testing.T, rec *gologtest.Recorder, n int) gologtest.Entries {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for rec.Len() < n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	entries := rec.All()
+	if len(entries) != n {
+		t.Fatalf("len(entries) = %d want %d: %v", len(entries), n, entries)
+	}
+	return entries
+}
+
+// Test that unary interceptors properly log calls.
+func TestUnaryInterceptor(t *testing.T) {
+	t.Parallel()
+
+	server, client := gologtest.NewRecorder(), gologtest.NewRecorder()
+	conn := newConn(t, server, client)
+	c := healthpb.NewHealthClient(conn)
+
+	if _, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("c.Check() error: %v", err)
+	}
+	_, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	if err == nil {
+		t.Fatalf("c.Check(unknown) succeed")
+	}
+
+	method := "/grpc.health.v1.Health/Check"
+	entries := waitEntries(t, server, 4)
+	if m := entries[0].Map(); m["msg"] != "checking" || m["method"] != method || m["kind"] != "server" {
+		t.Errorf("server entries[0] = %v", m)
+	}
+	if m := entries[1].Map(); entries[1].Level != golog.LevelInfo || m["code"] != "OK" ||
+		m["sent"] != 1 || m["received"] != 1 || m["peer"] == nil {
+		t.Errorf("server entries[1] = %v %v", entries[1].Level, m)
+	}
+	if m := entries[3].Map(); entries[3].Level != golog.LevelWarn || m["code"] != codes.NotFound.String() ||
+		m["sent"] != 0 || m["error"] == nil {
+		t.Errorf("server entries[3] = %v %v", entries[3].Level, m)
+	}
+
+	entries = waitEntries(t, client, 2)
+	if m := entries[0].Map(); m["kind"] != "client" || m["method"] != method || m["code"] != "OK" || m["peer"] != "bufconn" {
+		t.Errorf("client entries[0] = %v", m)
+	}
+	if entries[1].Level != golog.LevelWarn {
+		t.Errorf("client entries[1].Level = %v want %v", entries[1].Level, golog.LevelWarn)
+	}
+}
+
+// Test that stream interceptors properly log calls with message counts.
+func TestStreamInterceptor(t *testing.T) {
+	t.Parallel()
+
+	server, client := gologtest.NewRecorder(), gologtest.NewRecorder()
+	conn := newConn(t, server, client, CodeLevel(func(code codes.Code) golog.Level {
+		return golog.LevelDebug
+	}))
+	c := healthpb.NewHealthClient(conn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := c.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatalf("c.Watch() error: %v", err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatalf("stream.Recv() error: %v", err)
+	}
+	cancel()
+	if _, err := stream.Recv(); err == nil {
+		t.Fatalf("stream.Recv() after cancel succeed")
+	}
+
+	entries := waitEntries(t, client, 1)
+	if m := entries[0].Map(); entries[0].Level != golog.LevelDebug || m["code"] != codes.Canceled.String() ||
+		m["sent"] != 1 || m["received"] != 1 {
+		t.Errorf("client entries[0] = %v %v", entries[0].Level, m)
+	}
+	entries = waitEntries(t, server, 1)
+	if m := entries[0].Map(); m["method"] != "/grpc.health.v1.Health/Watch" || m["sent"] != 1 || m["received"] != 1 {
+		t.Errorf("server entries[0] = %v", m)
+	}
+}
+
+// Test that Payloads properly logs redacted messages.
+func TestPayloads(t *testing.T) {
+	t.Parallel()
+
+	server := gologtest.NewRecorder()
+	conn := newConn(t, server, golog.Discard, Payloads(func(msg interface{}) interface{} {
+		if req, ok := msg.(*healthpb.HealthCheckRequest); ok {
+			return "service=" + req.Service
+		}
+		return "redacted"
+	}))
+	c := healthpb.NewHealthClient(conn)
+	if _, err := c.Check(context.Background(), &healthpb.HealthCheckRequest{Service: ""}); err != nil {
+		t.Fatalf("c.Check() error: %v", err)
+	}
+
+	entries := waitEntries(t, server, 4).FilterByMsg(DefaultPayloadMsg)
+	if len(entries) != 2 {
+		t.Fatalf("len(payload entries) = %d want 2", len(entries))
+	}
+	if m := entries[0].Map(); entries[0].Level != golog.LevelDebug || m["direction"] != "recv" || m["payload"] != "service=" {
+		t.Errorf("entries[0] = %v", m)
+	}
+	if m := entries[1].Map(); m["direction"] != "send" || m["payload"] != "redacted" {
+		t.Errorf("entries[1] = %v", m)
+	}
+}
+
+// Test that client interceptors prefer the logger carried by context.
+func TestClientContextLogger(t *testing.T) {
+	t.Parallel()
+
+	client, ctxLogger := gologtest.NewRecorder(), gologtest.NewRecorder()
+	conn := newConn(t, golog.Discard, client)
+	c := healthpb.NewHealthClient(conn)
+
+	ctx := NewContext(context.Background(), ctxLogger)
+	if _, err := c.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatalf("c.Check() error: %v", err)
+	}
+	if got := client.Len(); got != 0 {
+		t.Errorf("client.Len() = %d want 0", got)
+	}
+	if got := ctxLogger.Len(); got != 1 {
+		t.Errorf("ctxLogger.Len() = %d want 1", got)
+	}
+}
+
+type failedClientStream struct {
+	grpc.ClientStream
+}
+
+func (failedClientStream) SendMsg(m interface{}) error {
+	return status.Error(codes.Unavailable, "send")
+}
+
+func (failedClientStream) RecvMsg(m interface{}) error {
+	return status.Error(codes.Unavailable, "recv")
+}
+
+// Test that client streams log once if SendMsg and RecvMsg fail concurrently.
+func TestClientStreamConcurrent(t *testing.T) {
+	t.Parallel()
+
+	rec := gologtest.NewRecorder()
+	c, _ := newCall(newOptions(nil), rec, "client", "/test")
+	s := &clientStream{ClientStream: failedClientStream{}, call: c, peer: &peer.Peer{}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_ = s.SendMsg(nil)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = s.RecvMsg(nil)
+		}()
+	}
+	wg.Wait()
+
+	if got := rec.Len(); got != 1 {
+		t.Errorf("rec.Len() = %d want 1", got)
+	}
+}
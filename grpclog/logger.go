@@ -0,0 +1,102 @@
+package grpclog
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/kibaamor/golog"
+	"google.golang.org/grpc/grpclog"
+)
+
+type loggerV2 struct {
+	logger    golog.Logger
+	helper    *golog.Helper
+	verbosity int
+	exit      func(code int)
+}
+
+var _ grpclog.LoggerV2 = (*loggerV2)(nil)
+
+// NewLoggerV2 new a grpclog.LoggerV2 which writes grpc-go internal logs
+// to logger, logs with verbosity greater than verbosity are discarded.
+// Logger is synced before exiting on fatal logs.
+//
+//	import (
+//		gologgrpc "github.com/kibaamor/golog/grpclog"
+//		"google.golang.org/grpc/grpclog"
+//	)
+//
+//	grpclog.SetLoggerV2(gologgrpc.NewLoggerV2(logger, 0))
+func NewLoggerV2(logger golog.Logger, verbosity int) grpclog.LoggerV2 {
+	logger = golog.WithHandler(logger, golog.HandlerFields("system", "grpc"))
+	return &loggerV2{logger: logger, helper: golog.NewHelper(logger), verbosity: verbosity, exit: os.Exit}
+}
+
+func (l *loggerV2) log(level golog.Level, msg string) {
+	l.helper.Log(level, golog.DefaultMsgKey, msg)
+}
+
+// fatal logs msg, syncs the logger and exits.
+func (l *loggerV2) fatal(msg string) {
+	l.log(golog.LevelFatal, msg)
+	_ = golog.Sync(l.logger)
+	l.exit(1)
+}
+
+func (l *loggerV2) Info(args ...interface{}) {
+	l.log(golog.LevelInfo, fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Infoln(args ...interface{}) {
+	l.log(golog.LevelInfo, sprintln(args...))
+}
+
+func (l *loggerV2) Infof(format string, args ...interface{}) {
+	l.log(golog.LevelInfo, fmt.Sprintf(format, args...))
+}
+
+func (l *loggerV2) Warning(args ...interface{}) {
+	l.log(golog.LevelWarn, fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Warningln(args ...interface{}) {
+	l.log(golog.LevelWarn, sprintln(args...))
+}
+
+func (l *loggerV2) Warningf(format string, args ...interface{}) {
+	l.log(golog.LevelWarn, fmt.Sprintf(format, args...))
+}
+
+func (l *loggerV2) Error(args ...interface{}) {
+	l.log(golog.LevelError, fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Errorln(args ...interface{}) {
+	l.log(golog.LevelError, sprintln(args...))
+}
+
+func (l *loggerV2) Errorf(format string, args ...interface{}) {
+	l.log(golog.LevelError, fmt.Sprintf(format, args...))
+}
+
+func (l *loggerV2) Fatal(args ...interface{}) {
+	l.fatal(fmt.Sprint(args...))
+}
+
+func (l *loggerV2) Fatalln(args ...interface{}) {
+	l.fatal(sprintln(args...))
+}
+
+func (l *loggerV2) Fatalf(format string, args ...interface{}) {
+	l.fatal(fmt.Sprintf(format, args...))
+}
+
+func (l *loggerV2) V(level int) bool {
+	return level <= l.verbosity
+}
+
+// sprintln formats like fmt.Sprintln without the trailing newline.
+func sprintln(args ...interface{}) string {
+	s := fmt.Sprintln(args...)
+	return s[:len(s)-1]
+}
@@ -0,0 +1,73 @@
+package grpclog
+
+import (
+	"testing"
+
+	"github.com/kibaamor/golog"
+	"github.com/kibaamor/golog/gologtest"
+)
+
+// Test that LoggerV2 properly writes logs.
+func TestLoggerV2(t *testing.T) {
+	t.Parallel()
+
+	rec := gologtest.NewRecorder()
+	l := NewLoggerV2(rec, 1)
+	l.Info("a", "b")
+	l.Warningln("a", "b")
+	l.Errorf("%d", 1)
+
+	entries := rec.All()
+	want := []struct {
+		level golog.Level
+		msg   string
+	}{
+		{golog.LevelInfo, "ab"},
+		{golog.LevelWarn, "a b"},
+		{golog.LevelError, "1"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("len(entries) = %d want %d", len(entries), len(want))
+	}
+	for i, w := range want {
+		if entries[i].Level != w.level || entries[i].Msg() != w.msg {
+			t.Errorf("entries[%d] = %v %q want %v %q", i, entries[i].Level, entries[i].Msg(), w.level, w.msg)
+		}
+		if v, _ := entries[i].Value("system"); v != "grpc" {
+			t.Errorf("entries[%d] system = %v want grpc", i, v)
+		}
+	}
+	if !l.V(1) || l.V(2) {
+		t.Errorf("V() does not respect verbosity")
+	}
+}
+
+type syncRecorder struct {
+	*gologtest.Recorder
+	synced int
+}
+
+func (r *syncRecorder) Sync() error {
+	r.synced = r.Len()
+	return nil
+}
+
+// Test that LoggerV2 syncs the logger before exiting on fatal logs.
+func TestLoggerV2Fatal(t *testing.T) {
+	t.Parallel()
+
+	rec := &syncRecorder{Recorder: gologtest.NewRecorder()}
+	l := NewLoggerV2(rec, 0).(*loggerV2)
+	code := -1
+	l.exit = func(c int) {
+		code = c
+	}
+
+	l.Fatalf("%s", "bye")
+	if rec.synced != 1 || code != 1 {
+		t.Errorf("synced %d logs and exited with %d, want 1 and 1", rec.synced, code)
+	}
+	if entries := rec.All(); entries[0].Level != golog.LevelFatal || entries[0].Msg() != "bye" {
+		t.Errorf("entries[0] = %v %q", entries[0].Level, entries[0].Msg())
+	}
+}
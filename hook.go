@@ -0,0 +1,169 @@
+package golog
+
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+)
+
+// Hook is fired on logs of specific levels, see WithHook.
+type Hook interface {
+	// Levels returns levels of logs the hook is fired on, all levels if empty.
+	Levels() []Level
+	// Fire is called with the log, kvs must not be modified.
+	Fire(level Level, kvs []interface{})
+}
+
+type hookFunc struct {
+	fire   func(level Level, kvs []interface{})
+	levels []Level
+}
+
+func (h *hookFunc) Levels() []Level {
+	return h.levels
+}
+
+func (h *hookFunc) Fire(level Level, kvs []interface{}) {
+	h.fire(level, kvs)
+}
+
+// NewHook new a hook which calls fire on logs of levels, or all levels if levels is empty.
+func NewHook(fire func(level Level, kvs []interface{}), levels ...Level) Hook {
+	return &hookFunc{fire: fire, levels: levels}
+}
+
+// hookLevel reports whether hook should be fired on level.
+func hookLevel(hook Hook, level Level) bool {
+	levels := hook.Levels()
+	if len(levels) == 0 {
+		return true
+	}
+	for _, l := range levels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
+
+// fireHook fires hook and reports its panic to fallback.
+func fireHook(hook Hook, level Level, kvs []interface{}, fallback Logger) {
+	defer func() {
+		if r := recover(); r != nil {
+			fallback.Log(LevelError,
+				DefaultMsgKey, "golog: hook panic",
+				"hook", fmt.Sprintf("%T", hook),
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+	hook.Fire(level, kvs)
+}
+
+// AsyncHook is a hook which fires the wrapped hook in a background goroutine.
+type AsyncHook struct {
+	hook     Hook
+	fallback Logger
+	queue    chan Record
+	done     chan struct{}
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	pending int
+	dropped uint64
+	closed  bool
+}
+
+// NewAsyncHook new a hook which fires hook asynchronously with a queue of size,
+// logs are dropped if the queue is full. Panics of hook are reported to fallback,
+// or to standard error if fallback is nil.
+func NewAsyncHook(hook Hook, size int, fallback Logger) *AsyncHook {
+	if fallback == nil {
+		fallback = NewStdLogger(os.Stderr)
+	}
+	h := &AsyncHook{
+		hook:     hook,
+		fallback: fallback,
+		queue:    make(chan Record, size),
+		done:     make(chan struct{}),
+	}
+	h.cond = sync.NewCond(&h.mu)
+	go h.run()
+	return h
+}
+
+func (h *AsyncHook) run() {
+	defer close(h.done)
+
+	for r := range h.queue {
+		fireHook(h.hook, r.Level, r.KVs, h.fallback)
+
+		h.mu.Lock()
+		h.pending--
+		if h.pending == 0 {
+			h.cond.Broadcast()
+		}
+		h.mu.Unlock()
+	}
+}
+
+// Levels returns levels of the wrapped hook.
+func (h *AsyncHook) Levels() []Level {
+	return h.hook.Levels()
+}
+
+// Fire queues the log to fire the wrapped hook, Lazy values are evaluated
+// before the log is queued.
+func (h *AsyncHook) Fire(level Level, kvs []interface{}) {
+	r := Record{Level: level, KVs: append([]interface{}(nil), resolveLazy(kvs)...)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		h.dropped++
+		return
+	}
+	select {
+	case h.queue <- r:
+		h.pending++
+	default:
+		h.dropped++
+	}
+}
+
+// Sync waits until all queued logs are fired.
+func (h *AsyncHook) Sync() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for h.pending > 0 {
+		h.cond.Wait()
+	}
+	return nil
+}
+
+// Dropped returns the number of logs dropped since the queue is full or the hook is closed.
+func (h *AsyncHook) Dropped() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.dropped
+}
+
+// Close fires all queued logs and stops the background goroutine.
+func (h *AsyncHook) Close() error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil
+	}
+	h.closed = true
+	close(h.queue)
+	h.mu.Unlock()
+
+	<-h.done
+	return nil
+}
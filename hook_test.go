@@ -0,0 +1,103 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Test that WithHook properly fires hooks after filters and handlers.
+func TestWithHook(t *testing.T) {
+	t.Parallel()
+
+	var fired []string
+	hook := NewHook(func(level Level, kvs []interface{}) {
+		fired = append(fired, level.String())
+		if got, want := len(kvs), 4; got != want {
+			t.Errorf("len(kvs) = %d want %d", got, want)
+		}
+	}, LevelError, LevelFatal)
+
+	buf := &bytes.Buffer{}
+	l := WithHook(NewStdLogger(buf), hook)
+	l = WithFilter(l, FilterLevel(LevelWarn))
+	l = WithHandler(l, HandlerFields("k2", "v2"))
+
+	for _, level := range []Level{LevelDebug, LevelWarn, LevelError, LevelFatal} {
+		l.Log(level, "k1", "v1")
+	}
+	if got, want := strings.Join(fired, ","), "ERROR,FATAL"; got != want {
+		t.Errorf("fired = %q want %q", got, want)
+	}
+	if got, want := strings.Count(buf.String(), "\n"), 3; got != want {
+		t.Errorf("buf.String() = %q want %d lines", buf.String(), want)
+	}
+}
+
+// Test that WithHook properly isolates hook panics.
+func TestWithHookPanic(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := WithHook(NewStdLogger(buf), NewHook(func(level Level, kvs []interface{}) {
+		panic("boom")
+	}))
+	l.Log(LevelInfo, "k1", "v1")
+
+	got := buf.String()
+	if !strings.HasPrefix(got, `ERROR, "msg": "golog: hook panic", "hook": "*golog.hookFunc", "panic": "boom"`) {
+		t.Errorf("buf.String() = %q want hook panic report", got)
+	}
+	if !strings.HasSuffix(got, "INFO, \"k1\": \"v1\"\n") {
+		t.Errorf("buf.String() = %q want log written", got)
+	}
+}
+
+// Test that AsyncHook properly fires hook asynchronously.
+func TestAsyncHook(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mu    sync.Mutex
+		fired []interface{}
+	)
+	started, block := make(chan struct{}, 2), make(chan struct{})
+	h := NewAsyncHook(NewHook(func(level Level, kvs []interface{}) {
+		started <- struct{}{}
+		<-block
+		mu.Lock()
+		fired = append(fired, kvs[1])
+		mu.Unlock()
+	}), 1, Discard)
+
+	kvs := []interface{}{"k", 1}
+	l := WithHook(Discard, h)
+	l.Log(LevelInfo, kvs...) // being fired
+	<-started
+	kvs[1] = 2
+	for i := 0; i < 100 && h.Dropped() == 0; i++ {
+		l.Log(LevelInfo, kvs...) // queued, then dropped
+	}
+	if h.Dropped() == 0 {
+		t.Errorf("h.Dropped() = 0 want dropped logs")
+	}
+	close(block)
+
+	if err := Sync(l); err != nil {
+		t.Fatalf("Sync() error: %v", err)
+	}
+	mu.Lock()
+	if got, want := len(fired), 2; got != want || fired[0] != 1 || fired[1] != 2 {
+		t.Errorf("fired = %v want [1 2]", fired)
+	}
+	mu.Unlock()
+
+	if err := h.Close(); err != nil {
+		t.Errorf("h.Close() error: %v", err)
+	}
+	l.Log(LevelInfo, kvs...)
+	if err := Sync(l); err != nil {
+		t.Errorf("Sync() after close error: %v", err)
+	}
+}
@@ -0,0 +1,528 @@
+package golog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// DefaultHTTPBatchCount is default maximum number of logs in a request.
+	DefaultHTTPBatchCount = 500
+	// DefaultHTTPBatchInterval is default interval to send a request.
+	DefaultHTTPBatchInterval = time.Second
+	// DefaultHTTPQueueSize is default maximum number of logs waiting to be sent.
+	DefaultHTTPQueueSize = 10000
+	// DefaultHTTPMaxRetries is default maximum number of retries of a request.
+	DefaultHTTPMaxRetries = 3
+	// DefaultHTTPMinBackoff is default minimum delay before retrying.
+	DefaultHTTPMinBackoff = 100 * time.Millisecond
+	// DefaultHTTPMaxBackoff is default maximum delay before retrying.
+	DefaultHTTPMaxBackoff = 10 * time.Second
+
+	errHTTPClosed = errors.New("golog: http: logger closed")
+)
+
+// HTTPFormat encode a batch of logs into request body.
+//
+// Keys of records are strings and values are snapshots taken when logs are
+// logged, they implement fmt.Stringer and json.Marshaler.
+type HTTPFormat interface {
+	// ContentType returns the Content-Type of request body.
+	ContentType() string
+	// Encode write records into buf.
+	Encode(buf *bytes.Buffer, records []Record) error
+}
+
+type ndjsonFormat struct{}
+
+// HTTPFormatNDJSON encode each log as a JSON object in a line.
+func HTTPFormatNDJSON() HTTPFormat {
+	return ndjsonFormat{}
+}
+
+func (ndjsonFormat) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (ndjsonFormat) Encode(buf *bytes.Buffer, records []Record) error {
+	for _, r := range records {
+		encodeJSON(buf, r.Level, r.KVs)
+		_ = buf.WriteByte('\n')
+	}
+	return nil
+}
+
+type elasticsearchFormat struct {
+	index string
+}
+
+// HTTPFormatElasticsearch encode logs for Elasticsearch _bulk API, each log is
+// indexed into index with its time in @timestamp.
+func HTTPFormatElasticsearch(index string) HTTPFormat {
+	return elasticsearchFormat{index: index}
+}
+
+func (elasticsearchFormat) ContentType() string {
+	return "application/x-ndjson"
+}
+
+func (f elasticsearchFormat) Encode(buf *bytes.Buffer, records []Record) error {
+	for _, r := range records {
+		_, _ = buf.WriteString(`{"index":{"_index":`)
+		writeJSONString(buf, f.index)
+		_, _ = buf.WriteString("}}\n")
+		encodeJSON(buf, r.Level, append([]interface{}{"@timestamp", r.Time.Format(time.RFC3339Nano)}, r.KVs...))
+		_ = buf.WriteByte('\n')
+	}
+	return nil
+}
+
+type lokiFormat struct {
+	labels    map[string]string
+	labelKeys []string
+}
+
+// HTTPFormatLoki encode logs for Loki push API. Each stream is labeled with
+// static labels, the level and the values of labelKeys, which are removed
+// from log line.
+func HTTPFormatLoki(labels map[string]string, labelKeys ...string) HTTPFormat {
+	return lokiFormat{labels: labels, labelKeys: labelKeys}
+}
+
+func (lokiFormat) ContentType() string {
+	return "application/json"
+}
+
+func (f lokiFormat) Encode(buf *bytes.Buffer, records []Record) error {
+	type stream struct {
+		labels map[string]string
+		values [][2]string
+	}
+	var streams []*stream
+	index := make(map[string]*stream)
+
+	var line bytes.Buffer
+	for _, r := range records {
+		labels := make(map[string]string, len(f.labels)+len(f.labelKeys)+1)
+		for k, v := range f.labels {
+			labels[k] = v
+		}
+		labels[DefaultLevelKey] = strings.ToLower(r.Level.String())
+		kvs := make([]interface{}, 0, len(r.KVs))
+		for i := 0; i < len(r.KVs); i += 2 {
+			k := fmt.Sprint(r.KVs[i])
+			if f.isLabel(k) {
+				labels[k] = fmt.Sprint(r.KVs[i+1])
+				continue
+			}
+			kvs = append(kvs, r.KVs[i], r.KVs[i+1])
+		}
+
+		line.Reset()
+		encodeJSON(&line, r.Level, kvs)
+		key := lokiLabelsKey(labels)
+		s, ok := index[key]
+		if !ok {
+			s = &stream{labels: labels}
+			index[key] = s
+			streams = append(streams, s)
+		}
+		s.values = append(s.values, [2]string{strconv.FormatInt(r.Time.UnixNano(), 10), line.String()})
+	}
+
+	_, _ = buf.WriteString(`{"streams":[`)
+	for i, s := range streams {
+		if i > 0 {
+			_ = buf.WriteByte(',')
+		}
+		_, _ = buf.WriteString(`{"stream":{`)
+		for j, k := range sortedKeys(s.labels) {
+			if j > 0 {
+				_ = buf.WriteByte(',')
+			}
+			writeJSONString(buf, k)
+			_ = buf.WriteByte(':')
+			writeJSONString(buf, s.labels[k])
+		}
+		_, _ = buf.WriteString(`},"values":[`)
+		for j, v := range s.values {
+			if j > 0 {
+				_ = buf.WriteByte(',')
+			}
+			_ = buf.WriteByte('[')
+			writeJSONString(buf, v[0])
+			_ = buf.WriteByte(',')
+			writeJSONString(buf, v[1])
+			_ = buf.WriteByte(']')
+		}
+		_, _ = buf.WriteString(`]}`)
+	}
+	_, _ = buf.WriteString(`]}`)
+	return nil
+}
+
+func (f lokiFormat) isLabel(key string) bool {
+	for _, k := range f.labelKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func lokiLabelsKey(labels map[string]string) string {
+	var sb strings.Builder
+	for _, k := range sortedKeys(labels) {
+		sb.WriteString(strconv.Quote(k) + "=" + strconv.Quote(labels[k]) + ",")
+	}
+	return sb.String()
+}
+
+// httpValue is a snapshot of a log value, so that values changed after
+// logged are sent as they were.
+type httpValue struct {
+	text string
+	json []byte
+}
+
+func snapshotHTTPValue(v interface{}) httpValue {
+	var buf bytes.Buffer
+	writeJSONValue(&buf, v)
+	return httpValue{text: fmt.Sprint(v), json: buf.Bytes()}
+}
+
+func (v httpValue) String() string {
+	return v.text
+}
+
+func (v httpValue) MarshalJSON() ([]byte, error) {
+	return v.json, nil
+}
+
+// HTTPOption is HTTP logger option.
+type HTTPOption func(l *HTTPLogger)
+
+// HTTPFormatter set the format of request body, default is HTTPFormatNDJSON.
+func HTTPFormatter(format HTTPFormat) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.format = format
+	}
+}
+
+// HTTPClient set the client to send requests, default is http.DefaultClient.
+func HTTPClient(client *http.Client) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.client = client
+	}
+}
+
+// HTTPHeader add a header to requests, such as Authorization.
+func HTTPHeader(key, value string) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.header.Add(key, value)
+	}
+}
+
+// HTTPGzip compress request body with gzip.
+func HTTPGzip() HTTPOption {
+	return func(l *HTTPLogger) {
+		l.gzip = true
+	}
+}
+
+// HTTPBatch set the maximum number of logs in a request and the interval
+// to send a request, a request is sent as soon as either limit is reached.
+// DefaultHTTPBatchInterval is used if interval is not positive.
+func HTTPBatch(count int, interval time.Duration) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.batchCount = count
+		l.interval = interval
+	}
+}
+
+// HTTPQueue set the maximum number of logs waiting to be sent,
+// new logs are dropped when it is exceeded.
+func HTTPQueue(size int) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.queueSize = size
+	}
+}
+
+// HTTPRetry set the maximum number of retries of a request failed with
+// network errors, 429 or 5xx status, and the minimum and maximum delay
+// before retrying, the delay doubles after each failure.
+func HTTPRetry(maxRetries int, min, max time.Duration) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.maxRetries = maxRetries
+		l.minBackoff = min
+		l.maxBackoff = max
+	}
+}
+
+// HTTPFallback set the logger to report errors of sending logs in background,
+// default is standard error. Errors are reported at most once in
+// DefaultErrorReportInterval.
+func HTTPFallback(fallback Logger) HTTPOption {
+	return func(l *HTTPLogger) {
+		l.fallback = fallback
+	}
+}
+
+// HTTPLogger is a logger which sends logs to HTTP endpoint in batches,
+// such as Loki, Elasticsearch or a generic webhook.
+type HTTPLogger struct {
+	url        string
+	format     HTTPFormat
+	client     *http.Client
+	header     http.Header
+	gzip       bool
+	batchCount int
+	interval   time.Duration
+	queueSize  int
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	now        func() time.Time
+	fallback   Logger
+	reporter   *errorReporter
+
+	mu      sync.Mutex
+	records []Record
+	closed  bool
+	dropped uint64
+
+	sendMu  sync.Mutex
+	flushCh chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+var _ Logger = (*HTTPLogger)(nil)
+
+// NewHTTPLogger new a logger which POSTs logs to url in batches.
+func NewHTTPLogger(url string, opts ...HTTPOption) *HTTPLogger {
+	l := &HTTPLogger{
+		url:        url,
+		format:     HTTPFormatNDJSON(),
+		client:     http.DefaultClient,
+		header:     make(http.Header),
+		batchCount: DefaultHTTPBatchCount,
+		interval:   DefaultHTTPBatchInterval,
+		queueSize:  DefaultHTTPQueueSize,
+		maxRetries: DefaultHTTPMaxRetries,
+		minBackoff: DefaultHTTPMinBackoff,
+		maxBackoff: DefaultHTTPMaxBackoff,
+		now:        time.Now,
+		flushCh:    make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	if l.interval <= 0 {
+		l.interval = DefaultHTTPBatchInterval
+	}
+	l.reporter = newErrorReporter("http", l.fallback)
+
+	l.wg.Add(1)
+	go l.run()
+	return l
+}
+
+// Log write the kv pairs log.
+func (l *HTTPLogger) Log(level Level, kvs ...interface{}) {
+	if len(kvs) == 0 {
+		return
+	}
+
+	kvs = normalizeKVs(DefaultKeyPolicy, kvs)
+
+	r := Record{
+		Time:  l.now(),
+		Level: level,
+		KVs:   make([]interface{}, len(kvs)),
+	}
+	for i := 0; i < len(kvs); i += 2 {
+		r.KVs[i] = fmt.Sprint(kvs[i])
+		r.KVs[i+1] = snapshotHTTPValue(kvs[i+1])
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed || len(l.records) >= l.queueSize {
+		atomic.AddUint64(&l.dropped, 1)
+		return
+	}
+	l.records = append(l.records, r)
+	if len(l.records) >= l.batchCount {
+		select {
+		case l.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Dropped returns the number of logs dropped since the queue is full
+// or the request is failed after all retries.
+func (l *HTTPLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// Flush sends all the queued logs.
+func (l *HTTPLogger) Flush() error {
+	return l.send()
+}
+
+// Sync sends all the queued logs.
+func (l *HTTPLogger) Sync() error {
+	return l.Flush()
+}
+
+// Close flushes the queued logs and stops the logger.
+func (l *HTTPLogger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return errHTTPClosed
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	close(l.done)
+	l.wg.Wait()
+	return l.send()
+}
+
+func (l *HTTPLogger) run() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		case <-l.flushCh:
+		}
+		if err := l.send(); err != nil {
+			l.reporter.report(err)
+		}
+	}
+}
+
+func (l *HTTPLogger) send() error {
+	l.sendMu.Lock()
+	defer l.sendMu.Unlock()
+
+	l.mu.Lock()
+	records := l.records
+	l.records = nil
+	l.mu.Unlock()
+
+	var firstErr error
+	for len(records) > 0 {
+		n := l.batchCount
+		if n <= 0 || n > len(records) {
+			n = len(records)
+		}
+		if err := l.post(records[:n]); err != nil {
+			atomic.AddUint64(&l.dropped, uint64(n))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		records = records[n:]
+	}
+	return firstErr
+}
+
+// post sends records in a request, retries with backoff on failure.
+func (l *HTTPLogger) post(records []Record) error {
+	var body bytes.Buffer
+	if l.gzip {
+		var raw bytes.Buffer
+		if err := l.format.Encode(&raw, records); err != nil {
+			return err
+		}
+		zw := gzip.NewWriter(&body)
+		_, _ = zw.Write(raw.Bytes())
+		if err := zw.Close(); err != nil {
+			return err
+		}
+	} else if err := l.format.Encode(&body, records); err != nil {
+		return err
+	}
+
+	backoff := l.minBackoff
+	for attempt := 0; ; attempt++ {
+		retry, wait, err := l.do(body.Bytes())
+		if err == nil || !retry || attempt >= l.maxRetries {
+			return err
+		}
+		if wait <= 0 {
+			wait = backoff
+			if backoff *= 2; backoff > l.maxBackoff {
+				backoff = l.maxBackoff
+			}
+		}
+		time.Sleep(wait)
+	}
+}
+
+// do sends a request, reports whether it should be retried and how long to wait.
+func (l *HTTPLogger) do(body []byte) (retry bool, wait time.Duration, err error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, l.url, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, err
+	}
+	for k, v := range l.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", l.format.ContentType())
+	if l.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return true, 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, 0, nil
+	}
+	err = fmt.Errorf("unexpected status %s", resp.Status)
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+		return false, 0, err
+	}
+	if seconds, perr := strconv.Atoi(resp.Header.Get("Retry-After")); perr == nil {
+		if wait = time.Duration(seconds) * time.Second; wait > l.maxBackoff {
+			wait = l.maxBackoff
+		}
+	}
+	return true, wait, err
+}
@@ -0,0 +1,252 @@
+package golog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type httpRequest struct {
+	header http.Header
+	body   string
+}
+
+// newHTTPServer new a server records requests and responds with statuses in turn.
+func newHTTPServer(t *testing.T, statuses ...int) (*httptest.Server, func() []httpRequest) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var requests []httpRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Errorf("gzip.NewReader() error: %v", err)
+				return
+			}
+			body = zr
+		}
+		data, _ := io.ReadAll(body)
+
+		mu.Lock()
+		defer mu.Unlock()
+		requests = append(requests, httpRequest{header: r.Header, body: string(data)})
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []httpRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]httpRequest(nil), requests...)
+	}
+}
+
+func httpTestNow() time.Time {
+	return time.Unix(1666946270, 786000000).UTC()
+}
+
+// Test that HTTPLogger properly posts logs in supported formats.
+func TestHTTPLoggerFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		format      HTTPFormat
+		contentType string
+		want        string
+	}{
+		{
+			name:        "NDJSON",
+			format:      HTTPFormatNDJSON(),
+			contentType: "application/x-ndjson",
+			want: `{"level":"INFO","msg":"m1","app":"a1"}` + "\n" +
+				`{"level":"ERROR","msg":"m2","app":"a2"}` + "\n",
+		},
+		{
+			name:        "Elasticsearch",
+			format:      HTTPFormatElasticsearch("logs"),
+			contentType: "application/x-ndjson",
+			want: `{"index":{"_index":"logs"}}` + "\n" +
+				`{"level":"INFO","@timestamp":"2022-10-28T08:37:50.786Z","msg":"m1","app":"a1"}` + "\n" +
+				`{"index":{"_index":"logs"}}` + "\n" +
+				`{"level":"ERROR","@timestamp":"2022-10-28T08:37:50.786Z","msg":"m2","app":"a2"}` + "\n",
+		},
+		{
+			name:        "Loki",
+			format:      HTTPFormatLoki(map[string]string{"job": "test"}, "app"),
+			contentType: "application/json",
+			want: `{"streams":[` +
+				`{"stream":{"app":"a1","job":"test","level":"info"},` +
+				`"values":[["1666946270786000000","{\"level\":\"INFO\",\"msg\":\"m1\"}"]]},` +
+				`{"stream":{"app":"a2","job":"test","level":"error"},` +
+				`"values":[["1666946270786000000","{\"level\":\"ERROR\",\"msg\":\"m2\"}"]]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, requests := newHTTPServer(t)
+			l := NewHTTPLogger(srv.URL, HTTPFormatter(tt.format), HTTPGzip(), HTTPHeader("X-Token", "t1"))
+			l.now = httpTestNow
+
+			l.Log(LevelInfo, DefaultMsgKey, "m1", "app", "a1")
+			l.Log(LevelError, DefaultMsgKey, "m2", "app", "a2")
+			if err := l.Close(); err != nil {
+				t.Fatalf("l.Close() error: %v", err)
+			}
+
+			reqs := requests()
+			if len(reqs) != 1 {
+				t.Fatalf("len(requests) = %d want 1", len(reqs))
+			}
+			if got := reqs[0].header.Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q want %q", got, tt.contentType)
+			}
+			if got := reqs[0].header.Get("X-Token"); got != "t1" {
+				t.Errorf("X-Token = %q want %q", got, "t1")
+			}
+			if got := reqs[0].body; got != tt.want {
+				t.Errorf("body = %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test that HTTPLogger properly retries on 429 and 5xx.
+func TestHTTPLoggerRetry(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newHTTPServer(t, http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusOK)
+	l := NewHTTPLogger(srv.URL, HTTPRetry(3, time.Millisecond, time.Millisecond))
+	l.Log(LevelInfo, "k1", "v1")
+	if err := l.Flush(); err != nil {
+		t.Fatalf("l.Flush() error: %v", err)
+	}
+	if got, want := len(requests()), 3; got != want {
+		t.Errorf("len(requests) = %d want %d", got, want)
+	}
+	if got := l.Dropped(); got != 0 {
+		t.Errorf("l.Dropped() = %d want 0", got)
+	}
+
+	srv, requests = newHTTPServer(t, http.StatusBadRequest)
+	l = NewHTTPLogger(srv.URL, HTTPRetry(3, time.Millisecond, time.Millisecond))
+	l.Log(LevelInfo, "k1", "v1")
+	if err := l.Close(); err == nil {
+		t.Errorf("l.Close() with bad request succeed")
+	}
+	if got, want := len(requests()), 1; got != want {
+		t.Errorf("len(requests) = %d want %d", got, want)
+	}
+	if got := l.Dropped(); got != 1 {
+		t.Errorf("l.Dropped() = %d want 1", got)
+	}
+}
+
+// Test that HTTPLogger reports errors of sending in background to fallback.
+func TestHTTPLoggerFallback(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newHTTPServer(t, http.StatusBadRequest)
+	buf := &bytes.Buffer{}
+	l := NewHTTPLogger(srv.URL, HTTPBatch(1, time.Hour), HTTPFallback(NewStdLogger(buf)))
+	l.Log(LevelInfo, "k1", "v1")
+	for i := 0; i < 1000 && l.Dropped() == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("l.Close() error: %v", err)
+	}
+	if got := buf.String(); !strings.HasPrefix(got, `ERROR, "msg": "golog: http error"`) {
+		t.Errorf("buf.String() = %q want error report", got)
+	}
+}
+
+// Test that HTTPLogger properly sends batches and bounds queue.
+func TestHTTPLoggerBatch(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newHTTPServer(t)
+	l := NewHTTPLogger(srv.URL, HTTPBatch(2, time.Hour), HTTPQueue(3))
+
+	block := make(chan struct{})
+	l.sendMu.Lock() // hold sending, so logs are queued
+	go func() {
+		<-block
+		l.sendMu.Unlock()
+	}()
+	for i := 0; i < 5; i++ {
+		l.Log(LevelInfo, "i", i)
+	}
+	if got, want := l.Dropped(), uint64(2); got != want {
+		t.Errorf("l.Dropped() = %d want %d", got, want)
+	}
+	close(block)
+
+	if err := l.Close(); err != nil {
+		t.Fatalf("l.Close() error: %v", err)
+	}
+	reqs := requests()
+	if len(reqs) != 2 {
+		t.Fatalf("len(requests) = %d want 2", len(reqs))
+	}
+	want := `{"level":"INFO","i":0}` + "\n" + `{"level":"INFO","i":1}` + "\n"
+	if reqs[0].body != want {
+		t.Errorf("requests[0].body = %q want %q", reqs[0].body, want)
+	}
+	if l.Log(LevelInfo, "k1", "v1"); l.Dropped() != 3 {
+		t.Errorf("l.Dropped() = %d want 3", l.Dropped())
+	}
+}
+
+// Test that HTTPLogger uses default interval if batch interval is not positive.
+func TestHTTPLoggerBatchZeroInterval(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newHTTPServer(t)
+	l := NewHTTPLogger(srv.URL, HTTPBatch(10, 0))
+	if l.interval != DefaultHTTPBatchInterval {
+		t.Errorf("l.interval = %v want %v", l.interval, DefaultHTTPBatchInterval)
+	}
+
+	l.Log(LevelInfo, "k1", "v1")
+	if err := l.Close(); err != nil {
+		t.Fatalf("l.Close() error: %v", err)
+	}
+	if got := len(requests()); got != 1 {
+		t.Errorf("len(requests) = %d want 1", got)
+	}
+}
+
+// Test that HTTPLogger sends values as they were logged.
+func TestHTTPLoggerSnapshot(t *testing.T) {
+	t.Parallel()
+
+	srv, requests := newHTTPServer(t)
+	l := NewHTTPLogger(srv.URL, HTTPBatch(10, time.Hour))
+
+	m := map[string]int{"a": 1}
+	l.Log(LevelInfo, "m", m, "err", errors.New("e1"))
+	m["a"] = 2
+	if err := l.Close(); err != nil {
+		t.Fatalf("l.Close() error: %v", err)
+	}
+	reqs := requests()
+	if len(reqs) != 1 {
+		t.Fatalf("len(requests) = %d want 1", len(reqs))
+	}
+	if got, want := reqs[0].body, `{"level":"INFO","m":{"a":1},"err":"e1"}`+"\n"; got != want {
+		t.Errorf("body = %q want %q", got, want)
+	}
+}
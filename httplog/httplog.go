@@ -0,0 +1,256 @@
+// Package httplog provides net/http access log middleware based on golog.
+package httplog
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/kibaamor/golog"
+)
+
+var (
+	// DefaultRequestIDHeader is default header of request id.
+	DefaultRequestIDHeader = "X-Request-ID"
+	// DefaultMsg is default message of access log.
+	DefaultMsg = "http request"
+)
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx which carries logger.
+func NewContext(ctx context.Context, logger golog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger helper carried by ctx, it logs
+// nothing if ctx carries no logger.
+func FromContext(ctx context.Context) *golog.Helper {
+	if logger, ok := ctx.Value(contextKey{}).(golog.Logger); ok {
+		return golog.NewHelper(logger)
+	}
+	return golog.NewHelper(golog.Discard)
+}
+
+// Option is middleware option.
+type Option func(m *middleware)
+
+// StatusLevel set the level of access logs with status in class,
+// which is the first digit of status, such as 4 for 4xx.
+// Default is INFO for 1xx, 2xx and 3xx, WARN for 4xx and ERROR for 5xx.
+func StatusLevel(class int, level golog.Level) Option {
+	return func(m *middleware) {
+		m.levels[class] = level
+	}
+}
+
+// RequestIDHeader set the header of request id, default is DefaultRequestIDHeader.
+// Request id is generated if it is missing in request.
+func RequestIDHeader(header string) Option {
+	return func(m *middleware) {
+		m.requestIDHeader = header
+	}
+}
+
+// SkipPaths skip access logs of requests with path equals to any of paths,
+// or has prefix of any path ends with '/'. Skipped requests still carry the
+// logger in their context.
+func SkipPaths(paths ...string) Option {
+	return func(m *middleware) {
+		m.skip = append(m.skip, func(r *http.Request) bool {
+			for _, p := range paths {
+				if r.URL.Path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(r.URL.Path, p)) {
+					return true
+				}
+			}
+			return false
+		})
+	}
+}
+
+// SkipFunc skip access logs of requests if fn returns true.
+func SkipFunc(fn func(r *http.Request) bool) Option {
+	return func(m *middleware) {
+		m.skip = append(m.skip, fn)
+	}
+}
+
+type middleware struct {
+	logger          golog.Logger
+	next            http.Handler
+	levels          map[int]golog.Level
+	requestIDHeader string
+	skip            []func(r *http.Request) bool
+	now             func() time.Time
+}
+
+// Middleware returns a middleware which logs each request with method, path,
+// status, bytes, duration, remote address, user agent and request id.
+//
+// The logger decorated with request id, method and path is carried by
+// request context, and can be retrieved by FromContext. Panics of handler
+// are recovered and logged with stack.
+func Middleware(logger golog.Logger, opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		m := &middleware{
+			logger: logger,
+			next:   next,
+			levels: map[int]golog.Level{
+				1: golog.LevelInfo,
+				2: golog.LevelInfo,
+				3: golog.LevelInfo,
+				4: golog.LevelWarn,
+				5: golog.LevelError,
+			},
+			requestIDHeader: DefaultRequestIDHeader,
+			now:             time.Now,
+		}
+		for _, o := range opts {
+			o(m)
+		}
+		return m
+	}
+}
+
+func newRequestID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func (m *middleware) skipped(r *http.Request) bool {
+	for _, fn := range m.skip {
+		if fn(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *middleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := m.now()
+	requestID := r.Header.Get(m.requestIDHeader)
+	if requestID == "" {
+		requestID = newRequestID()
+	}
+	w.Header().Set(m.requestIDHeader, requestID)
+
+	logger := golog.WithHandler(m.logger, golog.HandlerFields(
+		"request_id", requestID,
+		"method", r.Method,
+		"path", r.URL.Path,
+	))
+	ctx := NewContext(r.Context(), logger)
+	if m.skipped(r) {
+		m.next.ServeHTTP(w, r.WithContext(ctx))
+		return
+	}
+
+	helper := golog.NewHelper(logger)
+	rw := &responseWriter{ResponseWriter: w}
+
+	defer func() {
+		if v := recover(); v != nil {
+			if v == http.ErrAbortHandler { //nolint:errorlint
+				panic(v)
+			}
+			helper.Log(golog.LevelError,
+				golog.DefaultMsgKey, "http handler panic",
+				"panic", fmt.Sprint(v),
+				"stack", string(debug.Stack()),
+			)
+			if rw.status == 0 {
+				rw.WriteHeader(http.StatusInternalServerError)
+			}
+		}
+
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		level, ok := m.levels[status/100]
+		if !ok {
+			level = golog.LevelInfo
+		}
+		helper.Log(level,
+			golog.DefaultMsgKey, DefaultMsg,
+			"status", status,
+			"bytes", rw.bytes,
+			"duration", m.now().Sub(start),
+			"remote_addr", r.RemoteAddr,
+			"user_agent", r.UserAgent(),
+		)
+	}()
+
+	m.next.ServeHTTP(rw, r.WithContext(ctx))
+}
+
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the final status, informational statuses such as
+// 103 Early Hints may be written before it.
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 && (status >= 200 || status == http.StatusSwitchingProtocols) {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker if the underlying writer does, the status
+// of hijacked requests is logged as 101 Switching Protocols.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("httplog: underlying writer does not implement http.Hijacker")
+	}
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
+
+// ReadFrom implements io.ReaderFrom, so the underlying writer may send
+// files with sendfile.
+func (w *responseWriter) ReadFrom(r io.Reader) (int64, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := io.Copy(w.ResponseWriter, r)
+	w.bytes += n
+	return n, err
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
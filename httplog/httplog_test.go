@@ -0,0 +1,225 @@
+package httplog
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kibaamor/golog"
+	"github.com/kibaamor/golog/gologtest"
+)
+
+// fixNow makes the clock of handler created by Middleware advance 1ms per call.
+func fixNow(handler http.Handler) {
+	now := time.Unix(0, 0)
+	handler.(*middleware).now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+}
+
+// Test that Middleware properly logs requests.
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	rec := gologtest.NewRecorder()
+	handler := Middleware(rec, StatusLevel(4, golog.LevelError))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("in handler")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+	}))
+	fixNow(handler)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("User-Agent", "test")
+	req.Header.Set(DefaultRequestIDHeader, "rid")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if got := w.Header().Get(DefaultRequestIDHeader); got != "rid" {
+		t.Errorf("response request id = %q want %q", got, "rid")
+	}
+
+	entries := rec.All()
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d want 2", len(entries))
+	}
+	if got := entries[0].Map(); got["msg"] != "in handler" || got["request_id"] != "rid" || got["path"] != "/users/1" {
+		t.Errorf("entries[0] = %v", got)
+	}
+	e := entries[1]
+	if e.Level != golog.LevelError {
+		t.Errorf("entries[1].Level = %v want %v", e.Level, golog.LevelError)
+	}
+	want := map[string]interface{}{
+		"msg":         DefaultMsg,
+		"request_id":  "rid",
+		"method":      http.MethodGet,
+		"path":        "/users/1",
+		"status":      http.StatusNotFound,
+		"bytes":       int64(9),
+		"duration":    time.Millisecond,
+		"remote_addr": req.RemoteAddr,
+		"user_agent":  "test",
+	}
+	got := e.Map()
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("entries[1][%q] = %v want %v", k, got[k], v)
+		}
+	}
+}
+
+// Test that Middleware properly recovers panics.
+func TestMiddlewarePanic(t *testing.T) {
+	t.Parallel()
+
+	rec := gologtest.NewRecorder()
+	handler := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("w.Code = %d want %d", w.Code, http.StatusInternalServerError)
+	}
+	entries := rec.FilterByLevel(golog.LevelError)
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d want 2", len(entries))
+	}
+	if stack, _ := entries[0].Value("stack"); stack == "" {
+		t.Errorf("panic log without stack")
+	}
+	if v, _ := entries[1].Value("status"); v != http.StatusInternalServerError {
+		t.Errorf("status = %v want %d", v, http.StatusInternalServerError)
+	}
+	if v, _ := entries[0].Value("request_id"); v == "" {
+		t.Errorf("request id is not generated")
+	}
+}
+
+// Test that Middleware properly skips requests.
+func TestMiddlewareSkip(t *testing.T) {
+	t.Parallel()
+
+	rec := gologtest.NewRecorder()
+	handler := Middleware(rec,
+		SkipPaths("/healthz", "/debug/"),
+		SkipFunc(func(r *http.Request) bool {
+			return r.Method == http.MethodOptions
+		}),
+	)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Debug("in handler")
+	}))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/healthz", nil),
+		httptest.NewRequest(http.MethodGet, "/debug/pprof", nil),
+		httptest.NewRequest(http.MethodOptions, "/users", nil),
+		httptest.NewRequest(http.MethodGet, "/healthz/x", nil),
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if got := rec.All().FilterByMsg("in handler").FilterByField("path", "/healthz"); len(got) != 1 {
+		t.Errorf("logs of skipped handler = %v want 1", got)
+	}
+	entries := rec.All().FilterByMsg(DefaultMsg)
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d want 1", len(entries))
+	}
+	if v, _ := entries[0].Value("path"); v != "/healthz/x" {
+		t.Errorf("path = %v want /healthz/x", v)
+	}
+	if v, _ := entries[0].Value("status"); v != http.StatusOK {
+		t.Errorf("status = %v want %d", v, http.StatusOK)
+	}
+}
+
+type hijackRecorder struct {
+	*httptest.ResponseRecorder
+	hijacked bool
+}
+
+func (r *hijackRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	r.hijacked = true
+	return nil, nil, nil
+}
+
+// Test that Middleware keeps http.Hijacker and io.ReaderFrom of the writer.
+func TestMiddlewareWriterInterfaces(t *testing.T) {
+	t.Parallel()
+
+	rec := gologtest.NewRecorder()
+	handler := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ws" {
+			h, ok := w.(http.Hijacker)
+			if !ok {
+				t.Fatal("writer does not implement http.Hijacker")
+			}
+			if _, _, err := h.Hijack(); err != nil {
+				t.Errorf("Hijack() error: %v", err)
+			}
+			return
+		}
+		if _, err := w.(io.ReaderFrom).ReadFrom(strings.NewReader("hello")); err != nil {
+			t.Errorf("ReadFrom() error: %v", err)
+		}
+		if _, _, err := w.(http.Hijacker).Hijack(); err == nil {
+			t.Errorf("Hijack() of httptest.ResponseRecorder error = nil")
+		}
+	}))
+
+	w := &hijackRecorder{ResponseRecorder: httptest.NewRecorder()}
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ws", nil))
+	if !w.hijacked {
+		t.Errorf("underlying writer is not hijacked")
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/file", nil))
+	entries := rec.All()
+	if len(entries) != 2 {
+		t.Fatalf("len(entries) = %d want 2", len(entries))
+	}
+	if v, _ := entries[0].Value("status"); v != http.StatusSwitchingProtocols {
+		t.Errorf("status = %v want %d", v, http.StatusSwitchingProtocols)
+	}
+	if v, _ := entries[1].Value("bytes"); v != int64(5) {
+		t.Errorf("bytes = %v want 5", v)
+	}
+}
+
+// Test that Middleware logs the final status after informational ones.
+func TestMiddlewareInformationalStatus(t *testing.T) {
+	t.Parallel()
+
+	rec := gologtest.NewRecorder()
+	handler := Middleware(rec)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusEarlyHints)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	entries := rec.All()
+	if len(entries) != 1 {
+		t.Fatalf("len(entries) = %d want 1", len(entries))
+	}
+	if v, _ := entries[0].Value("status"); v != http.StatusInternalServerError || entries[0].Level != golog.LevelError {
+		t.Errorf("status = %v at %v want %d at %v", v, entries[0].Level, http.StatusInternalServerError, golog.LevelError)
+	}
+}
+
+// Test that FromContext properly returns a helper without logger.
+func TestFromContext(t *testing.T) {
+	t.Parallel()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	FromContext(req.Context()).Info("discarded")
+}
@@ -0,0 +1,235 @@
+//go:build linux
+// +build linux
+
+package golog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// DefaultJournaldAddr is default path of journald native protocol socket.
+var DefaultJournaldAddr = "/run/systemd/journal/socket"
+
+// JournaldOption is journald logger option.
+type JournaldOption func(l *JournaldLogger)
+
+// JournaldAddr set path of journald socket, default is DefaultJournaldAddr.
+func JournaldAddr(addr string) JournaldOption {
+	return func(l *JournaldLogger) {
+		l.addr = addr
+	}
+}
+
+// JournaldIdentifier set SYSLOG_IDENTIFIER of entries, default is the program name.
+func JournaldIdentifier(identifier string) JournaldOption {
+	return func(l *JournaldLogger) {
+		l.identifier = identifier
+	}
+}
+
+// JournaldFallback set the logger to report errors of writing logs, default is
+// standard error. Errors are reported at most once in DefaultErrorReportInterval.
+func JournaldFallback(fallback Logger) JournaldOption {
+	return func(l *JournaldLogger) {
+		l.fallback = fallback
+	}
+}
+
+// JournaldLogger is a logger which writes logs to systemd-journald with
+// the native protocol.
+//
+// The message is written to MESSAGE, the level is written to PRIORITY,
+// the caller information is written to CODE_FILE and CODE_LINE, the timestamp
+// is written to SYSLOG_TIMESTAMP and other keys are converted to uppercase fields.
+type JournaldLogger struct {
+	mu         sync.Mutex
+	addr       string
+	identifier string
+	conn       *net.UnixConn
+	raddr      *net.UnixAddr
+	buf        bytes.Buffer
+	fallback   Logger
+	reporter   *errorReporter
+}
+
+var _ Logger = (*JournaldLogger)(nil)
+
+// NewJournaldLogger new a logger which writes logs to systemd-journald.
+func NewJournaldLogger(opts ...JournaldOption) (*JournaldLogger, error) {
+	l := &JournaldLogger{addr: DefaultJournaldAddr}
+	if len(os.Args) > 0 {
+		l.identifier = os.Args[0][strings.LastIndexByte(os.Args[0], '/')+1:]
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	l.reporter = newErrorReporter("journald", l.fallback)
+
+	if _, err := os.Stat(l.addr); err != nil {
+		return nil, fmt.Errorf("golog: journald: %w", err)
+	}
+	l.raddr = &net.UnixAddr{Name: l.addr, Net: "unixgram"}
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("golog: journald: %w", err)
+	}
+	l.conn = conn
+	return l, nil
+}
+
+// journaldFieldName convert key to a valid journal field name.
+func journaldFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		default:
+			return '_'
+		}
+	}, key)
+	name = strings.TrimLeft(name, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "X" + name
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func (l *JournaldLogger) writeField(name, value string) {
+	if !strings.ContainsRune(value, '\n') {
+		_, _ = l.buf.WriteString(name + "=" + value + "\n")
+		return
+	}
+	var size [8]byte
+	binary.LittleEndian.PutUint64(size[:], uint64(len(value)))
+	_, _ = l.buf.WriteString(name + "\n")
+	_, _ = l.buf.Write(size[:])
+	_, _ = l.buf.WriteString(value + "\n")
+}
+
+// Log write the kv pairs log.
+func (l *JournaldLogger) Log(level Level, kvs ...interface{}) {
+	if len(kvs) == 0 {
+		return
+	}
+	if err := l.write(level, kvs); err != nil {
+		l.reporter.report(err)
+	}
+}
+
+// write encodes the log into an entry and sends it.
+func (l *JournaldLogger) write(level Level, kvs []interface{}) error {
+	kvs = normalizeKVs(DefaultKeyPolicy, kvs)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+
+	l.buf.Reset()
+	l.writeField("PRIORITY", fmt.Sprint(SyslogSeverity(level)))
+	if l.identifier != "" {
+		l.writeField("SYSLOG_IDENTIFIER", l.identifier)
+	}
+	for i := 0; i < len(kvs); i += 2 {
+		k := fmt.Sprint(kvs[i])
+		v := fmt.Sprint(kvs[i+1])
+		switch k {
+		case DefaultMsgKey:
+			l.writeField("MESSAGE", v)
+		case DefaultTimestampKeyName:
+			l.writeField("SYSLOG_TIMESTAMP", v)
+		case DefaultCallerKeyName:
+			if index := strings.LastIndexByte(v, ':'); index >= 0 {
+				l.writeField("CODE_FILE", v[:index])
+				l.writeField("CODE_LINE", v[index+1:])
+			} else {
+				l.writeField("CODE_FILE", v)
+			}
+		default:
+			l.writeField(journaldFieldName(k), v)
+		}
+	}
+
+	return l.send(l.buf.Bytes())
+}
+
+// send writes entry as a datagram, or passes it with a file descriptor
+// if it is too large for a datagram.
+func (l *JournaldLogger) send(entry []byte) error {
+	_, _, err := l.conn.WriteMsgUnix(entry, nil, l.raddr)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, syscall.EMSGSIZE) && !errors.Is(err, syscall.ENOBUFS) {
+		return err
+	}
+
+	f, err := journaldEntryFile(entry)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, _, err = l.conn.WriteMsgUnix(nil, unix.UnixRights(int(f.Fd())), l.raddr)
+	return err
+}
+
+// journaldEntryFile write entry into a sealed memfd, or an unlinked temporary
+// file if memfd is not supported.
+func journaldEntryFile(entry []byte) (*os.File, error) {
+	if fd, err := unix.MemfdCreate("golog-journald", unix.MFD_CLOEXEC|unix.MFD_ALLOW_SEALING); err == nil {
+		f := os.NewFile(uintptr(fd), "golog-journald")
+		if _, err := f.Write(entry); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		seals := unix.F_SEAL_SHRINK | unix.F_SEAL_GROW | unix.F_SEAL_WRITE | unix.F_SEAL_SEAL
+		if _, err := unix.FcntlInt(f.Fd(), unix.F_ADD_SEALS, seals); err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return f, nil
+	}
+
+	f, err := os.CreateTemp("/dev/shm", "golog-journald-")
+	if err != nil {
+		if f, err = os.CreateTemp("", "golog-journald-"); err != nil {
+			return nil, err
+		}
+	}
+	_ = os.Remove(f.Name())
+	if _, err := f.Write(entry); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Close closes the connection to journald.
+func (l *JournaldLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close()
+	l.conn = nil
+	return err
+}
@@ -0,0 +1,44 @@
+//go:build !linux
+// +build !linux
+
+package golog
+
+import "errors"
+
+// JournaldOption is journald logger option.
+type JournaldOption func(l *JournaldLogger)
+
+// JournaldAddr set path of journald socket.
+func JournaldAddr(addr string) JournaldOption {
+	return func(l *JournaldLogger) {}
+}
+
+// JournaldIdentifier set SYSLOG_IDENTIFIER of entries.
+func JournaldIdentifier(identifier string) JournaldOption {
+	return func(l *JournaldLogger) {}
+}
+
+// JournaldFallback set the logger to report errors of writing logs.
+func JournaldFallback(fallback Logger) JournaldOption {
+	return func(l *JournaldLogger) {}
+}
+
+// JournaldLogger is a logger which writes logs to systemd-journald,
+// it is only supported on linux.
+type JournaldLogger struct{}
+
+var _ Logger = (*JournaldLogger)(nil)
+
+// NewJournaldLogger always returns error since journald is only supported on linux.
+func NewJournaldLogger(opts ...JournaldOption) (*JournaldLogger, error) {
+	return nil, errors.New("golog: journald: not supported on this platform")
+}
+
+// Log does nothing.
+func (l *JournaldLogger) Log(level Level, kvs ...interface{}) {
+}
+
+// Close does nothing.
+func (l *JournaldLogger) Close() error {
+	return nil
+}
@@ -0,0 +1,185 @@
+//go:build linux
+// +build linux
+
+package golog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// parseJournaldEntry parse native protocol entry into fields.
+func parseJournaldEntry(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	fields := make(map[string]string)
+	for len(data) > 0 {
+		index := bytes.IndexAny(data, "=\n")
+		if index < 0 {
+			t.Fatalf("invalid entry %q", data)
+		}
+		name := string(data[:index])
+		if data[index] == '=' {
+			end := bytes.IndexByte(data, '\n')
+			fields[name] = string(data[index+1 : end])
+			data = data[end+1:]
+			continue
+		}
+		size := binary.LittleEndian.Uint64(data[index+1 : index+9])
+		fields[name] = string(data[index+9 : index+9+int(size)])
+		data = data[index+9+int(size)+1:]
+	}
+	return fields
+}
+
+func listenJournald(t *testing.T) (*net.UnixConn, string) {
+	t.Helper()
+
+	addr := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: addr, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("net.ListenUnixgram() error: %v", err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+	})
+	return conn, addr
+}
+
+// readJournaldEntry read an entry from datagram or passed file descriptor.
+func readJournaldEntry(t *testing.T, conn *net.UnixConn) map[string]string {
+	t.Helper()
+
+	buf := make([]byte, 1<<20)
+	oob := make([]byte, unix.CmsgSpace(4))
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, oobn, _, _, err := conn.ReadMsgUnix(buf, oob)
+	if err != nil {
+		t.Fatalf("conn.ReadMsgUnix() error: %v", err)
+	}
+	if oobn == 0 {
+		return parseJournaldEntry(t, buf[:n])
+	}
+
+	msgs, err := unix.ParseSocketControlMessage(oob[:oobn])
+	if err != nil || len(msgs) != 1 {
+		t.Fatalf("unix.ParseSocketControlMessage() error: %v", err)
+	}
+	fds, err := unix.ParseUnixRights(&msgs[0])
+	if err != nil || len(fds) != 1 {
+		t.Fatalf("unix.ParseUnixRights() error: %v", err)
+	}
+	f := os.NewFile(uintptr(fds[0]), "entry")
+	defer f.Close()
+	data, err := io.ReadAll(io.NewSectionReader(f, 0, 1<<30))
+	if err != nil {
+		t.Fatalf("io.ReadAll() error: %v", err)
+	}
+	return parseJournaldEntry(t, data)
+}
+
+// Test that JournaldLogger properly writes entries.
+func TestJournaldLogger(t *testing.T) {
+	t.Parallel()
+
+	conn, addr := listenJournald(t)
+	l, err := NewJournaldLogger(JournaldAddr(addr), JournaldIdentifier("app"))
+	if err != nil {
+		t.Fatalf("NewJournaldLogger() error: %v", err)
+	}
+	defer l.Close()
+
+	l.Log(LevelError,
+		DefaultTimestampKeyName, "2022-10-28T16:37:50.786+08:00",
+		DefaultCallerKeyName, "example.go:33",
+		DefaultMsgKey, "hello\nworld",
+		"user.id", 42,
+		"_hidden", "v",
+	)
+	got := readJournaldEntry(t, conn)
+	want := map[string]string{
+		"PRIORITY":          "3",
+		"SYSLOG_IDENTIFIER": "app",
+		"SYSLOG_TIMESTAMP":  "2022-10-28T16:37:50.786+08:00",
+		"CODE_FILE":         "example.go",
+		"CODE_LINE":         "33",
+		"MESSAGE":           "hello\nworld",
+		"USER_ID":           "42",
+		"HIDDEN":            "v",
+	}
+	if len(got) != len(want) {
+		t.Errorf("entry = %q want %q", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("entry[%q] = %q want %q", k, got[k], v)
+		}
+	}
+}
+
+// Test that JournaldLogger properly passes large entries with file descriptor.
+func TestJournaldLoggerLarge(t *testing.T) {
+	t.Parallel()
+
+	conn, addr := listenJournald(t)
+	l, err := NewJournaldLogger(JournaldAddr(addr))
+	if err != nil {
+		t.Fatalf("NewJournaldLogger() error: %v", err)
+	}
+	defer l.Close()
+
+	large := strings.Repeat("x", 4<<20)
+	l.Log(LevelInfo, DefaultMsgKey, large)
+	got := readJournaldEntry(t, conn)
+	if got["MESSAGE"] != large {
+		t.Errorf("len(entry[MESSAGE]) = %d want %d", len(got["MESSAGE"]), len(large))
+	}
+}
+
+// Test that journaldFieldName properly convert keys.
+func TestJournaldFieldName(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"k1":                    "K1",
+		"http.status":           "HTTP_STATUS",
+		"__x":                   "X",
+		"1a":                    "X1A",
+		"":                      "X",
+		strings.Repeat("a", 70): strings.Repeat("A", 64),
+	}
+	for key, want := range tests {
+		if got := journaldFieldName(key); got != want {
+			t.Errorf("journaldFieldName(%q) = %q want %q", key, got, want)
+		}
+	}
+}
+
+// Test that JournaldLogger reports errors to fallback.
+func TestJournaldLoggerFallback(t *testing.T) {
+	t.Parallel()
+
+	conn, addr := listenJournald(t)
+	buf := &bytes.Buffer{}
+	l, err := NewJournaldLogger(JournaldAddr(addr), JournaldFallback(NewStdLogger(buf)))
+	if err != nil {
+		t.Fatalf("NewJournaldLogger() error: %v", err)
+	}
+	defer l.Close()
+	conn.Close()
+
+	l.Log(LevelInfo, DefaultMsgKey, "m1")
+	l.Log(LevelInfo, DefaultMsgKey, "m2")
+	if got := buf.String(); strings.Count(got, "\n") != 1 || !strings.HasPrefix(got, `ERROR, "msg": "golog: journald error"`) {
+		t.Errorf("buf.String() = %q want one error report", got)
+	}
+}
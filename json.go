@@ -0,0 +1,85 @@
+package golog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+)
+
+// DefaultLevelKey is default log key for level in structured output.
+var DefaultLevelKey = "level"
+
+type jsonLogger struct {
+	log  *log.Logger
+	pool *sync.Pool
+}
+
+// NewJSONLogger new a logger which writes each log as a JSON object in a line.
+func NewJSONLogger(w io.Writer) Logger {
+	return &jsonLogger{
+		log: log.New(w, "", 0),
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return new(bytes.Buffer)
+			},
+		},
+	}
+}
+
+// Log write the kv pairs log.
+func (l *jsonLogger) Log(level Level, kvs ...interface{}) {
+	if len(kvs) == 0 {
+		return
+	}
+
+	kvs = normalizeKVs(DefaultKeyPolicy, kvs)
+
+	buf := l.pool.Get().(*bytes.Buffer)
+	encodeJSON(buf, level, kvs)
+	outputLevel(l.log, level, buf)
+	buf.Reset()
+	l.pool.Put(buf)
+}
+
+// encodeJSON write kv pairs into buf as a JSON object, kvs must be paired.
+func encodeJSON(buf *bytes.Buffer, level Level, kvs []interface{}) {
+	_, _ = buf.WriteString(`{`)
+	writeJSONString(buf, DefaultLevelKey)
+	_ = buf.WriteByte(':')
+	writeJSONString(buf, level.String())
+	for i := 0; i < len(kvs); i += 2 {
+		_ = buf.WriteByte(',')
+		writeJSONString(buf, fmt.Sprint(kvs[i]))
+		_ = buf.WriteByte(':')
+		writeJSONValue(buf, kvs[i+1])
+	}
+	_ = buf.WriteByte('}')
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) {
+	b, _ := json.Marshal(s)
+	_, _ = buf.Write(b)
+}
+
+func writeJSONValue(buf *bytes.Buffer, v interface{}) {
+	if err, ok := v.(error); ok {
+		if _, ok := v.(json.Marshaler); !ok {
+			writeJSONString(buf, err.Error())
+			return
+		}
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		writeJSONString(buf, fmt.Sprintf("%+v", v))
+		return
+	}
+	_, _ = buf.Write(b)
+}
+
+// Sync flushes buffered logs of the writer.
+func (l *jsonLogger) Sync() error {
+	return syncWriter(l.log.Writer())
+}
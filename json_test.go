@@ -0,0 +1,65 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// Test that jsonLogger properly record logs.
+func TestJSONLogger(t *testing.T) {
+	t.Parallel()
+
+	level := LevelInfo
+	tests := []struct {
+		name string
+		kvs  []interface{}
+		want string
+	}{
+		{
+			name: "Empty key value",
+			kvs:  nil,
+			want: "",
+		},
+		{
+			name: "One key value",
+			kvs:  []interface{}{"key1", "value1"},
+			want: `{"level":"INFO","key1":"value1"}` + "\n",
+		},
+		{
+			name: "Two key values",
+			kvs:  []interface{}{"k1", 1, "k2", []int{1, 2}},
+			want: `{"level":"INFO","k1":1,"k2":[1,2]}` + "\n",
+		},
+		{
+			name: "One key without value",
+			kvs:  []interface{}{"k1"},
+			want: `{"level":"INFO","k1":"KEY VALUES UNPAIRED"}` + "\n",
+		},
+		{
+			name: "Non string key and error value",
+			kvs:  []interface{}{1, errors.New("e1")},
+			want: `{"level":"INFO","1":"e1"}` + "\n",
+		},
+		{
+			name: "Unsupported value",
+			kvs:  []interface{}{"ch", make(chan int)},
+			want: `{"level":"INFO","ch":"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewJSONLogger(&buf)
+
+			log.Log(level, tt.kvs...)
+			got := buf.String()
+			if tt.name == "Unsupported value" {
+				got = got[:len(tt.want)]
+			}
+			if got != tt.want {
+				t.Errorf("buf.String() = %q want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package golog
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DuplicateKeyPolicy control how HandlerDedupKeys handle duplicate keys.
+type DuplicateKeyPolicy int8
+
+const (
+	// DuplicateKeepFirst keep the first kv pair of duplicate keys.
+	DuplicateKeepFirst DuplicateKeyPolicy = iota
+	// DuplicateKeepLast keep the last kv pair of duplicate keys.
+	DuplicateKeepLast
+	// DuplicateRename keep all kv pairs and rename duplicate keys with
+	// DefaultDuplicateKeySuffix and a sequence number, e.g. "ts_1".
+	DuplicateRename
+)
+
+// DefaultDuplicateKeySuffix is the suffix of keys renamed by DuplicateRename.
+var DefaultDuplicateKeySuffix = "_"
+
+// HandlerDedupKeys remove or rename kv pairs with duplicate keys, keys are
+// compared after being converted to string. The order of kv pairs is kept.
+func HandlerDedupKeys(policy DuplicateKeyPolicy) Handler {
+	return func(level Level, kvs []interface{}) []interface{} {
+		return dedupKeys(policy, kvs)
+	}
+}
+
+func dedupKeys(policy DuplicateKeyPolicy, kvs []interface{}) []interface{} {
+	n := len(kvs) &^ 1
+	seen := make(map[string]int, n/2)
+	dup := false
+	for i := 0; i < n; i += 2 {
+		k := fmt.Sprint(kvs[i])
+		if _, ok := seen[k]; ok {
+			dup = true
+		}
+		seen[k] = i
+	}
+	if !dup {
+		return kvs
+	}
+
+	var out []interface{}
+	switch policy {
+	case DuplicateKeepLast:
+		out = keepLastKeys(seen, kvs[:n])
+	case DuplicateRename:
+		out = renameKeys(seen, kvs[:n])
+	default:
+		out = keepFirstKeys(len(seen), kvs[:n])
+	}
+	return append(out, kvs[n:]...)
+}
+
+// keepFirstKeys returns the first pairs of keys, size is the number of keys.
+func keepFirstKeys(size int, kvs []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(kvs)+1)
+	kept := make(map[string]bool, size)
+	for i := 0; i < len(kvs); i += 2 {
+		k := fmt.Sprint(kvs[i])
+		if !kept[k] {
+			kept[k] = true
+			out = append(out, kvs[i], kvs[i+1])
+		}
+	}
+	return out
+}
+
+// keepLastKeys returns the last pairs of keys, seen is the index of the last pair of keys.
+func keepLastKeys(seen map[string]int, kvs []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(kvs)+1)
+	for i := 0; i < len(kvs); i += 2 {
+		if seen[fmt.Sprint(kvs[i])] == i {
+			out = append(out, kvs[i], kvs[i+1])
+		}
+	}
+	return out
+}
+
+// renameKeys renames duplicate keys with DefaultDuplicateKeySuffix and a number.
+func renameKeys(seen map[string]int, kvs []interface{}) []interface{} {
+	out := make([]interface{}, 0, len(kvs)+1)
+	used := make(map[string]bool, len(seen))
+	for k := range seen {
+		used[k] = true
+	}
+	kept := make(map[string]bool, len(seen))
+	for i := 0; i < len(kvs); i += 2 {
+		k := fmt.Sprint(kvs[i])
+		if !kept[k] {
+			kept[k] = true
+			out = append(out, kvs[i], kvs[i+1])
+			continue
+		}
+		for j := 1; ; j++ {
+			if name := k + DefaultDuplicateKeySuffix + strconv.Itoa(j); !used[name] {
+				used[name] = true
+				out = append(out, name, kvs[i+1])
+				break
+			}
+		}
+	}
+	return out
+}
+
+// KeyPolicy control how encoders handle non-string keys and unpaired kvs.
+type KeyPolicy int8
+
+const (
+	// KeyStringify convert keys to string and pair the last key without value
+	// with "KEY VALUES UNPAIRED".
+	KeyStringify KeyPolicy = iota
+	// KeyBadKey treat non-string keys and the last key without value as values
+	// of DefaultBadKey, as log/slog does.
+	KeyBadKey
+	// KeyPanic panic on non-string keys and unpaired kvs, for development.
+	KeyPanic
+)
+
+var (
+	// DefaultKeyPolicy is the key policy used by all encoders.
+	DefaultKeyPolicy = KeyStringify
+	// DefaultBadKey is the key of values with KeyBadKey.
+	DefaultBadKey = "!BADKEY"
+)
+
+// HandlerKeyPolicy normalize kvs with policy, so kvs are paired and,
+// except with KeyStringify, all keys are string. Groups in place of kv
+// pairs are expanded and Lazy values are evaluated too, so it should be
+// the last handler before logs are encoded.
+func HandlerKeyPolicy(policy KeyPolicy) Handler {
+	return func(level Level, kvs []interface{}) []interface{} {
+		return normalizeKVs(policy, kvs)
+	}
+}
+
+// kvsProblem returns the description of the first problem of kvs.
+func kvsProblem(kvs []interface{}) string {
+	for i := 0; i < len(kvs); i += 2 {
+		if _, ok := kvs[i].(string); !ok {
+			return fmt.Sprintf("non-string key %v (%T) at index %d", kvs[i], kvs[i], i)
+		}
+	}
+	if len(kvs)&1 == 1 {
+		return fmt.Sprintf("key %v without value", kvs[len(kvs)-1])
+	}
+	return ""
+}
+
+// normalizeKVs pairs kvs with policy and evaluates Lazy values, it is used by
+// encoders.
+func normalizeKVs(policy KeyPolicy, kvs []interface{}) []interface{} {
+	return resolveLazy(pairKVs(policy, kvs))
+}
+
+// pairKVs expands groups in place of kv pairs and pairs kvs with policy,
+// Lazy values are kept, so it can be used before filters.
+func pairKVs(policy KeyPolicy, kvs []interface{}) []interface{} {
+	kvs = expandGroups(kvs)
+	switch policy {
+	case KeyBadKey:
+		if kvsProblem(kvs) == "" {
+			return kvs
+		}
+		out := make([]interface{}, 0, len(kvs)+2)
+		for i := 0; i < len(kvs); {
+			k, ok := kvs[i].(string)
+			if !ok || i+1 == len(kvs) {
+				out = append(out, DefaultBadKey, kvs[i])
+				i++
+				continue
+			}
+			out = append(out, k, kvs[i+1])
+			i += 2
+		}
+		return out
+	case KeyPanic:
+		if problem := kvsProblem(kvs); problem != "" {
+			panic("golog: " + problem)
+		}
+		return kvs
+	default:
+		if len(kvs)&1 == 1 {
+			return append(kvs, "KEY VALUES UNPAIRED")
+		}
+		return kvs
+	}
+}
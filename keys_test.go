@@ -0,0 +1,97 @@
+package golog
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test that HandlerDedupKeys properly handle duplicate keys.
+func TestHandlerDedupKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy DuplicateKeyPolicy
+		kvs    []interface{}
+		want   []interface{}
+	}{
+		{
+			name:   "Without duplicate",
+			policy: DuplicateKeepFirst,
+			kvs:    []interface{}{"k1", 1, "k2", 2},
+			want:   []interface{}{"k1", 1, "k2", 2},
+		},
+		{
+			name:   "Keep first",
+			policy: DuplicateKeepFirst,
+			kvs:    []interface{}{"ts", 1, "k1", 1, "ts", 2},
+			want:   []interface{}{"ts", 1, "k1", 1},
+		},
+		{
+			name:   "Keep last",
+			policy: DuplicateKeepLast,
+			kvs:    []interface{}{"ts", 1, "k1", 1, "ts", 2},
+			want:   []interface{}{"k1", 1, "ts", 2},
+		},
+		{
+			name:   "Rename",
+			policy: DuplicateRename,
+			kvs:    []interface{}{"ts", 1, "ts_1", 0, "ts", 2, "ts", 3},
+			want:   []interface{}{"ts", 1, "ts_1", 0, "ts_2", 2, "ts_3", 3},
+		},
+		{
+			name:   "Non-string key and unpaired",
+			policy: DuplicateKeepFirst,
+			kvs:    []interface{}{1, 1, "1", 2, "k1"},
+			want:   []interface{}{1, 1, "k1"},
+		},
+	}
+	for _, tt := range tests {
+		if got := HandlerDedupKeys(tt.policy)(LevelInfo, tt.kvs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: HandlerDedupKeys() = %v want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Test that HandlerKeyPolicy properly normalize kvs.
+func TestHandlerKeyPolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy KeyPolicy
+		kvs    []interface{}
+		want   []interface{}
+	}{
+		{
+			name:   "Stringify",
+			policy: KeyStringify,
+			kvs:    []interface{}{1, 1, "k1"},
+			want:   []interface{}{1, 1, "k1", "KEY VALUES UNPAIRED"},
+		},
+		{
+			name:   "Bad key",
+			policy: KeyBadKey,
+			kvs:    []interface{}{"k1", 1, 2, "k2", 3, "k3"},
+			want:   []interface{}{"k1", 1, DefaultBadKey, 2, "k2", 3, DefaultBadKey, "k3"},
+		},
+		{
+			name:   "Bad key without problem",
+			policy: KeyBadKey,
+			kvs:    []interface{}{"k1", 1},
+			want:   []interface{}{"k1", 1},
+		},
+	}
+	for _, tt := range tests {
+		if got := HandlerKeyPolicy(tt.policy)(LevelInfo, tt.kvs); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: HandlerKeyPolicy() = %v want %v", tt.name, got, tt.want)
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != "golog: non-string key 1 (int) at index 0" {
+			t.Errorf("recover() = %v want panic", r)
+		}
+	}()
+	HandlerKeyPolicy(KeyPanic)(LevelInfo, []interface{}{1, 1})
+}
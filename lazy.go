@@ -0,0 +1,89 @@
+package golog
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// Lazy is a value evaluated only when the log is encoded, after all filters
+// are passed, so expensive values cost nothing if the log is discarded:
+//
+//	logger.Log(golog.LevelDebug, "dump", golog.Lazy(func() interface{} { return dump() }))
+//
+// Values of fmt.Stringer are formatted only when the log is encoded too.
+type Lazy func() interface{}
+
+// String formats the evaluated value.
+func (l Lazy) String() string {
+	return fmt.Sprint(Resolve(l))
+}
+
+// Resolve evaluates v until it is not a Lazy.
+func Resolve(v interface{}) interface{} {
+	for {
+		l, ok := v.(Lazy)
+		if !ok {
+			return v
+		}
+		if l == nil {
+			return nil
+		}
+		v = l()
+	}
+}
+
+// resolveLazy evaluates Lazy values of kvs, kvs is not modified.
+func resolveLazy(kvs []interface{}) []interface{} {
+	var out []interface{}
+	for i, v := range kvs {
+		if _, ok := v.(Lazy); !ok {
+			continue
+		}
+		if out == nil {
+			out = append([]interface{}(nil), kvs...)
+		}
+		out[i] = Resolve(v)
+	}
+	if out == nil {
+		return kvs
+	}
+	return out
+}
+
+// LazyTimestamp creates a Lazy of current timestamp, it is the lazy version
+// of HandlerTimestamp which can be used as a static field:
+//
+//	golog.WithHandler(logger, golog.HandlerFields("ts", golog.LazyTimestamp(format, time.Now)))
+func LazyTimestamp(valueFormat string, nowFunc func() time.Time) Lazy {
+	return func() interface{} {
+		return nowFunc().Format(valueFormat)
+	}
+}
+
+// LazyCaller creates a Lazy of caller information, it is the lazy version of
+// HandlerCaller which can be used as a static field. The caller is the first
+// frame outside golog, so there is no depth to set, it is empty if all frames
+// are in golog.
+func LazyCaller(withFullPath bool) Lazy {
+	return func() interface{} {
+		pcs := make([]uintptr, 32)
+		n := runtime.Callers(2, pcs)
+		frames := runtime.CallersFrames(pcs[:n])
+		for more := n > 0; more; {
+			var f runtime.Frame
+			f, more = frames.Next()
+			if isPackageFile(f.File) {
+				continue
+			}
+			file := f.File
+			if !withFullPath {
+				file = filepath.Base(file)
+			}
+			return file + ":" + strconv.Itoa(f.Line)
+		}
+		return ""
+	}
+}
@@ -0,0 +1,114 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that Lazy values are evaluated only after filters pass.
+func TestLazy(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	dump := Lazy(func() interface{} {
+		calls++
+		return Lazy(func() interface{} { return "big" })
+	})
+
+	var buf bytes.Buffer
+	l := WithFilter(NewJSONLogger(&buf), FilterLevel(LevelInfo))
+	l.Log(LevelDebug, "dump", dump)
+	if calls != 0 {
+		t.Errorf("calls = %d want 0 for filtered log", calls)
+	}
+	l.Log(LevelInfo, "dump", dump, Group("g", "k", dump))
+	if calls != 2 {
+		t.Errorf("calls = %d want 2", calls)
+	}
+	if got, want := buf.String(), `{"level":"INFO","dump":"big","g":{"k":"big"}}`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+
+	if got := Resolve(Lazy(nil)); got != nil {
+		t.Errorf("Resolve(nil) = %v want nil", got)
+	}
+	if got, want := dump.String(), "big"; got != want {
+		t.Errorf("dump.String() = %q want %q", got, want)
+	}
+}
+
+// Test that LazyTimestamp and LazyCaller properly work as static fields.
+func TestLazyFields(t *testing.T) {
+	t.Parallel()
+
+	now := func() time.Time {
+		return time.Date(2022, 10, 28, 16, 37, 50, 0, time.UTC)
+	}
+
+	var buf bytes.Buffer
+	l := WithHandler(NewStdLogger(&buf), HandlerFields(
+		"ts", LazyTimestamp(DefaultTimestampFormat, now),
+		"caller", LazyCaller(false),
+	))
+	NewHelper(l).Info("hello")
+
+	want := `INFO, "msg": "hello", "ts": "2022-10-28T16:37:50.000Z", "caller": "lazy_test.go:55"` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+
+	buf.Reset()
+	WithHandler(NewStdLogger(&buf), HandlerFields("caller", LazyCaller(true))).Log(LevelInfo)
+	if got := buf.String(); !strings.Contains(got, "/lazy_test.go:") {
+		t.Errorf("buf.String() = %q want full path", got)
+	}
+}
+
+// Test that WithGroup does not evaluate Lazy values of filtered logs.
+func TestLazyWithGroup(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	dump := Lazy(func() interface{} {
+		calls++
+		return "big"
+	})
+
+	var buf bytes.Buffer
+	l := WithGroup(WithFilter(NewJSONLogger(&buf), FilterLevel(LevelWarn)), "db")
+	l.Log(LevelDebug, "dump", dump)
+	if calls != 0 {
+		t.Errorf("calls = %d want 0 for filtered log", calls)
+	}
+	l.Log(LevelWarn, "dump", dump)
+	if calls != 1 {
+		t.Errorf("calls = %d want 1", calls)
+	}
+	if got, want := buf.String(), `{"level":"WARN","db":{"dump":"big"}}`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that Lazy values are evaluated before logs are passed to other goroutines.
+func TestLazyAsync(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewMultiLogger([]Logger{NewStdLogger(&buf)}, MultiParallel())
+	l.Log(LevelInfo, "caller", LazyCaller(false))
+	if got, want := buf.String(), `INFO, "caller": "lazy_test.go:100"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+
+	var fired []interface{}
+	h := NewAsyncHook(NewHook(func(level Level, kvs []interface{}) {
+		fired = kvs
+	}), 1, Discard)
+	WithHook(Discard, h).Log(LevelInfo, "caller", LazyCaller(false))
+	_ = h.Close()
+	if len(fired) != 2 || fired[1] != "lazy_test.go:109" {
+		t.Errorf("fired = %v want resolved caller", fired)
+	}
+}
@@ -1,5 +1,7 @@
 package golog
 
+import "time"
+
 // Logger is a logger interface.
 type Logger interface {
 	Log(level Level, kvs ...interface{})
@@ -13,3 +15,11 @@ type discard struct{}
 
 func (discard) Log(level Level, kvs ...interface{}) {
 }
+
+// Record is a log with the time it is logged, it is used by loggers
+// which process logs asynchronously.
+type Record struct {
+	Time  time.Time
+	Level Level
+	KVs   []interface{}
+}
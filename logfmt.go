@@ -0,0 +1,81 @@
+package golog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+type logfmtLogger struct {
+	log  *log.Logger
+	pool *sync.Pool
+}
+
+// NewLogfmtLogger new a logger which writes each log in logfmt format.
+func NewLogfmtLogger(w io.Writer) Logger {
+	return &logfmtLogger{
+		log: log.New(w, "", 0),
+		pool: &sync.Pool{
+			New: func() interface{} {
+				return new(bytes.Buffer)
+			},
+		},
+	}
+}
+
+// Log write the kv pairs log.
+func (l *logfmtLogger) Log(level Level, kvs ...interface{}) {
+	if len(kvs) == 0 {
+		return
+	}
+
+	kvs = flattenGroups(DefaultGroupSeparator, normalizeKVs(DefaultKeyPolicy, kvs))
+
+	buf := l.pool.Get().(*bytes.Buffer)
+	_, _ = buf.WriteString(DefaultLevelKey + "=" + level.String())
+	for i := 0; i < len(kvs); i += 2 {
+		_ = buf.WriteByte(' ')
+		_, _ = buf.WriteString(logfmtKey(fmt.Sprint(kvs[i])))
+		_ = buf.WriteByte('=')
+		_, _ = buf.WriteString(logfmtValue(fmt.Sprint(kvs[i+1])))
+	}
+	outputLevel(l.log, level, buf)
+	buf.Reset()
+	l.pool.Put(buf)
+}
+
+// logfmtKey replace characters not allowed in logfmt key with '_'.
+func logfmtKey(k string) string {
+	if k == "" {
+		return "_"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			return '_'
+		}
+		return r
+	}, k)
+}
+
+// logfmtValue quote value if needed.
+func logfmtValue(v string) string {
+	if v == "" {
+		return `""`
+	}
+	for _, r := range v {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || !unicode.IsPrint(r) {
+			return strconv.Quote(v)
+		}
+	}
+	return v
+}
+
+// Sync flushes buffered logs of the writer.
+func (l *logfmtLogger) Sync() error {
+	return syncWriter(l.log.Writer())
+}
@@ -0,0 +1,55 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Test that logfmtLogger properly record logs.
+func TestLogfmtLogger(t *testing.T) {
+	t.Parallel()
+
+	level := LevelInfo
+	tests := []struct {
+		name string
+		kvs  []interface{}
+		want string
+	}{
+		{
+			name: "Empty key value",
+			kvs:  nil,
+			want: "",
+		},
+		{
+			name: "One key value",
+			kvs:  []interface{}{"key1", "value1"},
+			want: "level=INFO key1=value1\n",
+		},
+		{
+			name: "Two key values",
+			kvs:  []interface{}{"k1", 1, "k2", 2},
+			want: "level=INFO k1=1 k2=2\n",
+		},
+		{
+			name: "One key without value",
+			kvs:  []interface{}{"k1"},
+			want: `level=INFO k1="KEY VALUES UNPAIRED"` + "\n",
+		},
+		{
+			name: "Quoted value",
+			kvs:  []interface{}{"k 1", "a=b", "k2", "", "k3", "a\nb"},
+			want: `level=INFO k_1="a=b" k2="" k3="a\nb"` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewLogfmtLogger(&buf)
+
+			log.Log(level, tt.kvs...)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("buf.String() = %q want %q", got, tt.want)
+			}
+		})
+	}
+}
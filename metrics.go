@@ -0,0 +1,177 @@
+package golog
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Dropper is implemented by loggers which may drop logs, such as NetLogger and HTTPLogger.
+type Dropper interface {
+	// Dropped returns the number of dropped logs.
+	Dropped() uint64
+}
+
+// MetricsSnapshot is a point-in-time copy of Metrics, it can be read by
+// exporters such as prometheus.Collector.
+type MetricsSnapshot struct {
+	// Records is the number of logs per level.
+	Records map[string]uint64 `json:"records"`
+	// Names is the number of logs per level per logger name recorded by Named.
+	Names map[string]map[string]uint64 `json:"names,omitempty"`
+	// Bytes is the number of bytes written through writers of Metrics.Writer.
+	Bytes uint64 `json:"bytes"`
+	// Duration is the time spent in wrapped loggers.
+	Duration time.Duration `json:"duration"`
+	// Dropped is the number of dropped logs per registered dropper.
+	Dropped map[string]uint64 `json:"dropped,omitempty"`
+}
+
+// MetricsOption is option for Metrics.
+type MetricsOption func(*Metrics)
+
+// MetricsByName sets whether logs are counted per logger name too.
+func MetricsByName(byName bool) MetricsOption {
+	return func(m *Metrics) {
+		m.byName = byName
+	}
+}
+
+// MetricsDropper registers a dropper whose dropped logs are reported with name.
+func MetricsDropper(name string, d Dropper) MetricsOption {
+	return func(m *Metrics) {
+		m.droppers[name] = d
+	}
+}
+
+// Metrics counts logs, bytes and time spent of loggers.
+type Metrics struct {
+	// accessed atomically, keep them first for 64-bit alignment
+	bytes    uint64
+	duration int64
+
+	byName   bool
+	droppers map[string]Dropper
+	now      func() time.Time
+
+	mu      sync.Mutex
+	records map[Level]uint64
+	names   map[string]map[Level]uint64
+}
+
+// NewMetrics new a metrics.
+func NewMetrics(opts ...MetricsOption) *Metrics {
+	m := &Metrics{
+		droppers: make(map[string]Dropper),
+		now:      time.Now,
+		records:  make(map[Level]uint64),
+		names:    make(map[string]map[Level]uint64),
+	}
+	for _, o := range opts {
+		o(m)
+	}
+	return m
+}
+
+func (m *Metrics) count(level Level, kvs []interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.records[level]++
+	if !m.byName {
+		return
+	}
+	var name string
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if k, ok := kvs[i].(string); ok && k == DefaultNameKey {
+			name = fmt.Sprint(kvs[i+1])
+		}
+	}
+	counts, ok := m.names[name]
+	if !ok {
+		counts = make(map[Level]uint64)
+		m.names[name] = counts
+	}
+	counts[level]++
+}
+
+// Snapshot returns a copy of current metrics.
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	s := MetricsSnapshot{
+		Records:  make(map[string]uint64),
+		Bytes:    atomic.LoadUint64(&m.bytes),
+		Duration: time.Duration(atomic.LoadInt64(&m.duration)),
+	}
+
+	m.mu.Lock()
+	for level, n := range m.records {
+		s.Records[level.String()] = n
+	}
+	if m.byName {
+		s.Names = make(map[string]map[string]uint64, len(m.names))
+		for name, counts := range m.names {
+			c := make(map[string]uint64, len(counts))
+			for level, n := range counts {
+				c[level.String()] = n
+			}
+			s.Names[name] = c
+		}
+	}
+	m.mu.Unlock()
+
+	if len(m.droppers) > 0 {
+		s.Dropped = make(map[string]uint64, len(m.droppers))
+		for name, d := range m.droppers {
+			s.Dropped[name] = d.Dropped()
+		}
+	}
+	return s
+}
+
+// Logger creates a logger that counts logs and time spent in logger.
+func (m *Metrics) Logger(logger Logger) Logger {
+	return &metricsLogger{logger: logger, metrics: m}
+}
+
+// Writer creates a writer that counts bytes written to w.
+func (m *Metrics) Writer(w io.Writer) io.Writer {
+	return &metricsWriter{w: w, metrics: m}
+}
+
+type metricsLogger struct {
+	logger  Logger
+	metrics *Metrics
+}
+
+func (l *metricsLogger) Log(level Level, kvs ...interface{}) {
+	l.metrics.count(level, kvs)
+
+	start := l.metrics.now()
+	l.logger.Log(level, kvs...)
+	atomic.AddInt64(&l.metrics.duration, int64(l.metrics.now().Sub(start)))
+}
+
+// Sync flushes buffered logs of the wrapped logger.
+func (l *metricsLogger) Sync() error {
+	return Sync(l.logger)
+}
+
+var _ Logger = (*metricsLogger)(nil)
+
+type metricsWriter struct {
+	w       io.Writer
+	metrics *Metrics
+}
+
+func (w *metricsWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	atomic.AddUint64(&w.metrics.bytes, uint64(n))
+	return n, err
+}
+
+// Sync flushes the wrapped writer.
+func (w *metricsWriter) Sync() error {
+	return syncWriter(w.w)
+}
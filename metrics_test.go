@@ -0,0 +1,43 @@
+package golog
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testDropper uint64
+
+func (d testDropper) Dropped() uint64 {
+	return uint64(d)
+}
+
+// Test that Metrics properly counts logs, bytes and time spent.
+func TestMetrics(t *testing.T) {
+	m := NewMetrics(MetricsByName(true), MetricsDropper("net", testDropper(3)))
+	now := time.Now()
+	m.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+	buf := &bytes.Buffer{}
+	root := m.Logger(NewStdLogger(m.Writer(buf)))
+	root.Log(LevelInfo, "k", "v")
+	root.Log(LevelError, "k", "v")
+	Named(root, "db").Log(LevelError, "k", "v")
+
+	want := MetricsSnapshot{
+		Records: map[string]uint64{"INFO": 1, "ERROR": 2},
+		Names: map[string]map[string]uint64{
+			"":   {"INFO": 1, "ERROR": 1},
+			"db": {"ERROR": 1},
+		},
+		Bytes:    uint64(buf.Len()),
+		Duration: 3 * time.Millisecond,
+		Dropped:  map[string]uint64{"net": 3},
+	}
+	if got := m.Snapshot(); !reflect.DeepEqual(got, want) {
+		t.Errorf("m.Snapshot() = %+v want %+v", got, want)
+	}
+}
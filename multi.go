@@ -1,17 +1,209 @@
 package golog
 
+import (
+	"fmt"
+	"os"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultMultiQueueSize is default maximum number of logs waiting for each
+// listed logger with MultiTimeout.
+var DefaultMultiQueueSize = 100
+
+// MultiOption is MultiLogger option.
+type MultiOption func(l *multiLogger)
+
+// MultiRecover recover panics of each listed logger and report them to
+// fallback, or to standard error if fallback is nil.
+func MultiRecover(fallback Logger) MultiOption {
+	return func(l *multiLogger) {
+		l.recover = true
+		l.fallback = fallback
+	}
+}
+
+// MultiTimeout stop waiting for a listed logger after d, the log operation
+// goes on with the next logger while the slow one keeps running in background.
+// Logs are queued for the slow logger, see MultiQueue.
+func MultiTimeout(d time.Duration) MultiOption {
+	return func(l *multiLogger) {
+		l.timeout = d
+	}
+}
+
+// MultiQueue set the maximum number of logs waiting for each listed logger
+// with MultiTimeout, new logs are dropped when it is exceeded.
+func MultiQueue(size int) MultiOption {
+	return func(l *multiLogger) {
+		l.queueSize = size
+	}
+}
+
+// MultiParallel log to all the listed loggers concurrently.
+func MultiParallel() MultiOption {
+	return func(l *multiLogger) {
+		l.parallel = true
+	}
+}
+
 type multiLogger struct {
-	loggers []Logger
+	// accessed atomically, keep it first for 64-bit alignment
+	dropped uint64
+
+	loggers   []Logger
+	workers   []*multiWorker
+	recover   bool
+	fallback  Logger
+	timeout   time.Duration
+	parallel  bool
+	queueSize int
 }
 
 func (t *multiLogger) Log(level Level, kvs ...interface{}) {
+	if !t.async() {
+		for _, l := range t.loggers {
+			l.Log(level, kvs...)
+		}
+		return
+	}
+
+	// loggers may still be running after return, so keep a private copy,
+	// Lazy values are evaluated here for the caller and time of the log
+	kvs = append(make([]interface{}, 0, len(kvs)), resolveLazy(kvs)...)
+	n := len(kvs)
+
+	if !t.parallel {
+		for _, w := range t.workers {
+			t.wait(t.start(w, level, kvs[:n:n]), w.logger)
+		}
+		return
+	}
+
+	done := make([]chan struct{}, len(t.workers))
+	for i, w := range t.workers {
+		done[i] = t.start(w, level, kvs[:n:n])
+	}
+	for i, w := range t.workers {
+		t.wait(done[i], w.logger)
+	}
+}
+
+// Dropped returns the number of logs dropped since queues of the listed
+// loggers are full.
+func (t *multiLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&t.dropped)
+}
+
+// Sync flushes buffered logs of all the listed loggers.
+func (t *multiLogger) Sync() error {
+	errs := make([]error, 0, len(t.loggers))
 	for _, l := range t.loggers {
-		l.Log(level, kvs...)
+		errs = append(errs, Sync(l))
+	}
+	return joinErrors(errs...)
+}
+
+// async reports whether the listed loggers run in their own goroutines.
+func (t *multiLogger) async() bool {
+	return t.parallel || t.timeout > 0
+}
+
+// plain reports whether t is created without any option.
+func (t *multiLogger) plain() bool {
+	return !t.recover && !t.async()
+}
+
+// start queues the log to w, the returned channel is closed when the log is
+// done, or nil if the log is dropped.
+func (t *multiLogger) start(w *multiWorker, level Level, kvs []interface{}) chan struct{} {
+	r := multiRecord{level: level, kvs: kvs, done: make(chan struct{})}
+	if !w.enqueue(r, t.timeout <= 0) {
+		atomic.AddUint64(&t.dropped, 1)
+		return nil
+	}
+	return r.done
+}
+
+func (t *multiLogger) wait(done chan struct{}, l Logger) {
+	if done == nil {
+		return
+	}
+	if t.timeout <= 0 {
+		<-done
+		return
+	}
+	timer := time.NewTimer(t.timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		t.fallback.Log(LevelError, DefaultMsgKey, "golog: logger timeout", "logger", loggerType(l), "timeout", t.timeout)
 	}
 }
 
 var _ Logger = (*multiLogger)(nil)
 
+type multiRecord struct {
+	level Level
+	kvs   []interface{}
+	done  chan struct{}
+}
+
+// multiWorker logs queued logs to logger in a goroutine, which is started
+// on demand and exits when the queue is empty.
+type multiWorker struct {
+	logger Logger
+	queue  chan multiRecord
+
+	mu      sync.Mutex
+	running bool
+}
+
+// enqueue queues r, it blocks if block is set and the queue is full,
+// otherwise reports false.
+func (w *multiWorker) enqueue(r multiRecord, block bool) bool {
+	if block {
+		w.queue <- r
+	} else {
+		select {
+		case w.queue <- r:
+		default:
+			return false
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.running {
+		w.running = true
+		go w.run()
+	}
+	return true
+}
+
+func (w *multiWorker) run() {
+	for {
+		select {
+		case r := <-w.queue:
+			w.logger.Log(r.level, r.kvs...)
+			close(r.done)
+			continue
+		default:
+		}
+
+		w.mu.Lock()
+		if len(w.queue) == 0 {
+			w.running = false
+			w.mu.Unlock()
+			return
+		}
+		w.mu.Unlock()
+	}
+}
+
 // MultiLogger creates a logger that duplicates its logs to all the
 // provided loggers, similar to the Unix tee(1) command.
 //
@@ -19,13 +211,94 @@ var _ Logger = (*multiLogger)(nil)
 // If a listed logger returns an error, that overall log operation
 // stops and returns the error; it does not continue down the list.
 func MultiLogger(loggers ...Logger) Logger {
+	return NewMultiLogger(loggers)
+}
+
+// NewMultiLogger creates a logger like MultiLogger with options.
+//
+// With MultiTimeout or MultiParallel, the listed loggers run in their
+// own goroutines, so their panics are always recovered and Lazy values
+// are evaluated before the log is passed to them. The logger implements
+// Dropper to report logs dropped with MultiQueue.
+func NewMultiLogger(loggers []Logger, opts ...MultiOption) Logger {
+	t := &multiLogger{queueSize: DefaultMultiQueueSize}
+	for _, o := range opts {
+		o(t)
+	}
+	if t.fallback == nil {
+		t.fallback = NewStdLogger(os.Stderr)
+	}
+
 	allLoggers := make([]Logger, 0, len(loggers))
 	for _, l := range loggers {
-		if ml, ok := l.(*multiLogger); ok {
+		if ml, ok := l.(*multiLogger); ok && ml.plain() {
 			allLoggers = append(allLoggers, ml.loggers...)
 		} else {
 			allLoggers = append(allLoggers, l)
 		}
 	}
-	return &multiLogger{allLoggers}
+	if t.recover || t.async() {
+		for i, l := range allLoggers {
+			allLoggers[i] = SafeLogger(l, t.fallback)
+		}
+	}
+	if t.async() {
+		if t.queueSize <= 0 {
+			t.queueSize = DefaultMultiQueueSize
+		}
+		t.workers = make([]*multiWorker, len(allLoggers))
+		for i, l := range allLoggers {
+			t.workers[i] = &multiWorker{logger: l, queue: make(chan multiRecord, t.queueSize)}
+		}
+	}
+	t.loggers = allLoggers
+	return t
+}
+
+type safeLogger struct {
+	logger   Logger
+	fallback Logger
+}
+
+func (l *safeLogger) Log(level Level, kvs ...interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			l.fallback.Log(LevelError,
+				DefaultMsgKey, "golog: logger panic",
+				"logger", loggerType(l.logger),
+				"panic", r,
+				"stack", string(debug.Stack()),
+			)
+		}
+	}()
+	l.logger.Log(level, kvs...)
+}
+
+// Sync flushes buffered logs of the wrapped logger, panics are recovered too.
+func (l *safeLogger) Sync() (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("golog: logger panic: %v", r)
+		}
+	}()
+	return Sync(l.logger)
+}
+
+var _ Logger = (*safeLogger)(nil)
+
+// SafeLogger creates a logger that recovers the panics of logger and
+// reports them to fallback, or to standard error if fallback is nil.
+func SafeLogger(logger, fallback Logger) Logger {
+	if fallback == nil {
+		fallback = NewStdLogger(os.Stderr)
+	}
+	return &safeLogger{logger: logger, fallback: fallback}
+}
+
+// loggerType returns the type name of logger for error reporting.
+func loggerType(l Logger) string {
+	if sl, ok := l.(*safeLogger); ok {
+		l = sl.logger
+	}
+	return fmt.Sprintf("%T", l)
 }
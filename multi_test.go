@@ -3,7 +3,10 @@ package golog
 import (
 	"bytes"
 	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 // Test that MultiLogger with multiple logger.
@@ -81,3 +84,105 @@ func TestMultiLoggerSingleChainFlatten(t *testing.T) {
 			4*(myDepth+2), logDepth)
 	}
 }
+
+// Test that MultiRecover properly recovers panic and goes on with remaining loggers.
+func TestMultiLoggerRecover(t *testing.T) {
+	var buf, fallback bytes.Buffer
+
+	l := NewMultiLogger([]Logger{
+		loggerFunc(func(level Level, kvs ...interface{}) {
+			panic("boom")
+		}),
+		NewStdLogger(&buf),
+	}, MultiRecover(NewStdLogger(&fallback)))
+
+	l.Log(LevelInfo, "k1", "v1")
+	if got, want := buf.String(), `INFO, "k1": "v1"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+	if got := fallback.String(); !strings.Contains(got, `"panic": "boom"`) {
+		t.Errorf("fallback.String() = %q want contains panic", got)
+	}
+}
+
+// Test that MultiTimeout properly stops waiting for slow logger.
+func TestMultiLoggerTimeout(t *testing.T) {
+	var buf, fallback bytes.Buffer
+
+	block := make(chan struct{})
+	defer close(block)
+	l := NewMultiLogger([]Logger{
+		loggerFunc(func(level Level, kvs ...interface{}) {
+			<-block
+		}),
+		NewStdLogger(&buf),
+	}, MultiTimeout(10*time.Millisecond), MultiRecover(NewStdLogger(&fallback)))
+
+	l.Log(LevelInfo, "k1", "v1")
+	if got, want := buf.String(), `INFO, "k1": "v1"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+	if got := fallback.String(); !strings.Contains(got, "golog: logger timeout") {
+		t.Errorf("fallback.String() = %q want contains timeout", got)
+	}
+}
+
+// Test that MultiQueue properly bounds logs waiting for slow logger.
+func TestMultiLoggerQueue(t *testing.T) {
+	started := make(chan struct{}, 1)
+	block := make(chan struct{})
+	defer close(block)
+	l := NewMultiLogger([]Logger{
+		loggerFunc(func(level Level, kvs ...interface{}) {
+			started <- struct{}{}
+			<-block
+		}),
+	}, MultiTimeout(time.Millisecond), MultiQueue(1), MultiRecover(Discard))
+
+	l.Log(LevelInfo, "k1", "v1")
+	<-started // the first log is running, the second one is queued
+	l.Log(LevelInfo, "k2", "v2")
+	l.Log(LevelInfo, "k3", "v3")
+	if got := l.(Dropper).Dropped(); got != 1 {
+		t.Errorf("Dropped() = %d want 1", got)
+	}
+}
+
+// Test that MultiParallel properly logs to all loggers concurrently.
+func TestMultiLoggerParallel(t *testing.T) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	logger := loggerFunc(func(level Level, kvs ...interface{}) {
+		// both loggers must be running at the same time to get here
+		wg.Done()
+		wg.Wait()
+		_ = append(kvs, "k2", "v2")
+	})
+
+	l := NewMultiLogger([]Logger{logger, logger}, MultiParallel(), MultiTimeout(time.Second))
+	done := make(chan struct{})
+	go func() {
+		l.Log(LevelInfo, "k1", "v1")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("parallel multiLogger did not return")
+	}
+}
+
+// Test that SafeLogger properly recovers panic.
+func TestSafeLogger(t *testing.T) {
+	var fallback bytes.Buffer
+
+	l := SafeLogger(loggerFunc(func(level Level, kvs ...interface{}) {
+		panic("boom")
+	}), NewStdLogger(&fallback))
+
+	l.Log(LevelInfo, "k1", "v1")
+	if got := fallback.String(); !strings.Contains(got, "golog: logger panic") {
+		t.Errorf("fallback.String() = %q want contains panic", got)
+	}
+}
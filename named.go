@@ -0,0 +1,161 @@
+package golog
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultNameKey is default log key for logger name.
+var DefaultNameKey = "logger"
+
+type namedLogger struct {
+	logger Logger
+	name   string
+}
+
+func (l *namedLogger) Log(level Level, kvs ...interface{}) {
+	l.logger.Log(level, append(kvs[:len(kvs):len(kvs)], DefaultNameKey, l.name)...)
+}
+
+// Sync flushes buffered logs of the wrapped logger.
+func (l *namedLogger) Sync() error {
+	return Sync(l.logger)
+}
+
+var _ Logger = (*namedLogger)(nil)
+
+// Named creates a logger that records name with key DefaultNameKey into log,
+// names of nested named loggers are joined with dot, e.g. "db.pool".
+//
+// Filters which check the name, such as the one of LevelSpec, should be
+// applied to the wrapped logger.
+func Named(logger Logger, name string) Logger {
+	if l, ok := logger.(*namedLogger); ok {
+		return &namedLogger{logger: l.logger, name: l.name + "." + name}
+	}
+	return &namedLogger{logger: logger, name: name}
+}
+
+type levelRule struct {
+	pattern string
+	level   Level
+}
+
+// LevelSpec is a set of level rules in the form of "pattern=level,...",
+// e.g. "db.*=debug,http=warn,*=info". Patterns are matched with path.Match,
+// and the longest matched pattern wins. It can be changed at runtime and
+// implements flag.Value.
+type LevelSpec struct {
+	rules atomic.Value // []levelRule
+}
+
+// NewLevelSpec creates a LevelSpec from spec.
+func NewLevelSpec(spec string) (*LevelSpec, error) {
+	s := &LevelSpec{}
+	if err := s.Set(spec); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Set replaces rules of s with spec, s is unchanged if spec is invalid.
+func (s *LevelSpec) Set(spec string) error {
+	var rules []levelRule
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		i := strings.LastIndexByte(item, '=')
+		if i < 0 {
+			return fmt.Errorf("golog: invalid level spec %q: missing '='", item)
+		}
+		pattern, name := strings.TrimSpace(item[:i]), strings.TrimSpace(item[i+1:])
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("golog: invalid level spec %q: %v", item, err)
+		}
+		level := ParseLevel(name)
+		if level.String() != strings.ToUpper(name) {
+			return fmt.Errorf("golog: invalid level spec %q: unknown level %q", item, name)
+		}
+		rules = append(rules, levelRule{pattern: pattern, level: level})
+	}
+	sort.SliceStable(rules, func(i, j int) bool {
+		return len(rules[i].pattern) > len(rules[j].pattern)
+	})
+	s.rules.Store(rules)
+	return nil
+}
+
+// String returns spec of s.
+func (s *LevelSpec) String() string {
+	rules, _ := s.rules.Load().([]levelRule)
+	items := make([]string, 0, len(rules))
+	for _, r := range rules {
+		items = append(items, r.pattern+"="+strings.ToLower(r.level.String()))
+	}
+	return strings.Join(items, ",")
+}
+
+// Level returns the level of name, ok is false if name is matched by no rule.
+func (s *LevelSpec) Level(name string) (level Level, ok bool) {
+	rules, _ := s.rules.Load().([]levelRule)
+	for _, r := range rules {
+		if matched, _ := path.Match(r.pattern, name); matched {
+			return r.level, true
+		}
+	}
+	return 0, false
+}
+
+// Filter creates a filter which discards logs with level less than the level
+// of logger name recorded by Named, logs matched by no rule are kept.
+func (s *LevelSpec) Filter() Filter {
+	return func(level Level, kvs []interface{}) bool {
+		var name string
+		for i := 0; i+1 < len(kvs); i += 2 {
+			if k, ok := kvs[i].(string); ok && k == DefaultNameKey {
+				name = fmt.Sprint(kvs[i+1])
+			}
+		}
+		l, ok := s.Level(name)
+		return ok && level < l
+	}
+}
+
+// FilterCaller creates a glog vmodule style filter which discards logs with
+// level less than the level of caller's source file. Patterns without slash
+// are matched against base name of the file without ".go", others are matched
+// against the same number of trailing path elements, e.g. "db/*" matches all
+// files in package directory db.
+//
+// The depth has the same meaning as HandlerCaller.
+func (s *LevelSpec) FilterCaller(depth int) Filter {
+	return func(level Level, kvs []interface{}) bool {
+		file, _ := caller(depth)
+		file = strings.TrimSuffix(file, ".go")
+
+		rules, _ := s.rules.Load().([]levelRule)
+		for _, r := range rules {
+			name := trailingPath(file, strings.Count(r.pattern, "/"))
+			if matched, _ := path.Match(r.pattern, name); matched {
+				return level < r.level
+			}
+		}
+		return false
+	}
+}
+
+// trailingPath returns the last n+1 elements of file.
+func trailingPath(file string, n int) string {
+	i := len(file)
+	for ; n >= 0; n-- {
+		if i = strings.LastIndexByte(file[:i], '/'); i < 0 {
+			return file
+		}
+	}
+	return file[i+1:]
+}
@@ -0,0 +1,131 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Test that Named properly records logger name.
+func TestNamed(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	root := NewStdLogger(buf)
+	Named(root, "db").Log(LevelInfo, "k1", "v1")
+	Named(Named(root, "db"), "pool").Log(LevelInfo)
+	if got, want := buf.String(), "INFO, \"k1\": \"v1\", \"logger\": \"db\"\nINFO, \"logger\": \"db.pool\"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that LevelSpec properly parses spec.
+func TestLevelSpecSet(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec string
+		want string
+		err  bool
+	}{
+		{spec: "", want: ""},
+		{spec: "*=info, db.*=DEBUG,http=warn", want: "db.*=debug,http=warn,*=info"},
+		{spec: "db", err: true},
+		{spec: "db=verbose", err: true},
+		{spec: "[=info", err: true},
+	}
+	for _, tt := range tests {
+		s, err := NewLevelSpec(tt.spec)
+		if (err != nil) != tt.err {
+			t.Errorf("NewLevelSpec(%q) error = %v want error %v", tt.spec, err, tt.err)
+			continue
+		}
+		if err == nil && s.String() != tt.want {
+			t.Errorf("NewLevelSpec(%q).String() = %q want %q", tt.spec, s.String(), tt.want)
+		}
+	}
+}
+
+// Test that LevelSpec.Filter properly filters logs by logger name.
+func TestLevelSpecFilter(t *testing.T) {
+	t.Parallel()
+
+	s, err := NewLevelSpec("db.*=debug,http=warn,*=info")
+	if err != nil {
+		t.Fatalf("NewLevelSpec() error: %v", err)
+	}
+
+	buf := &bytes.Buffer{}
+	root := WithFilter(NewStdLogger(buf), s.Filter())
+	db, http := Named(Named(root, "db"), "pool"), Named(root, "http")
+
+	db.Log(LevelDebug)
+	http.Log(LevelInfo)
+	http.Log(LevelWarn)
+	root.Log(LevelDebug, "k", "v")
+	root.Log(LevelInfo, "k", "v")
+	want := "DEBUG, \"logger\": \"db.pool\"\nWARN, \"logger\": \"http\"\nINFO, \"k\": \"v\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+
+	// change spec at runtime, logs matched by no rule are kept
+	buf.Reset()
+	if err := s.Set("http=debug"); err != nil {
+		t.Fatalf("s.Set() error: %v", err)
+	}
+	http.Log(LevelDebug)
+	root.Log(LevelDebug, "k", "v")
+	if got, want := buf.String(), "DEBUG, \"logger\": \"http\"\nDEBUG, \"k\": \"v\"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
+
+// Test that LevelSpec.FilterCaller properly filters logs by caller file.
+func TestLevelSpecFilterCaller(t *testing.T) {
+	t.Parallel()
+
+	d, w := "DEBUG, \"logger\": \"n\"\n", "WARN, \"logger\": \"n\"\n"
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{spec: "named_test=warn", want: w},
+		{spec: "named_*=debug,*=error", want: d + w},
+		{spec: "*/named_test=warn", want: w},
+		{spec: "other/*=warn", want: d + w},
+	}
+	for _, tt := range tests {
+		s, err := NewLevelSpec(tt.spec)
+		if err != nil {
+			t.Fatalf("NewLevelSpec(%q) error: %v", tt.spec, err)
+		}
+		buf := &bytes.Buffer{}
+		l := Named(WithFilter(NewStdLogger(buf), s.FilterCaller(DefaultCallerDepth)), "n")
+		l.Log(LevelDebug)
+		l.Log(LevelWarn)
+		if got := buf.String(); got != tt.want {
+			t.Errorf("%s: buf.String() = %q want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+// Test that trailingPath properly returns trailing path elements.
+func TestTrailingPath(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		file string
+		n    int
+		want string
+	}{
+		{file: "/a/b/c", n: 0, want: "c"},
+		{file: "/a/b/c", n: 1, want: "b/c"},
+		{file: "a/b/c", n: 2, want: "a/b/c"},
+		{file: "c", n: 0, want: "c"},
+	}
+	for _, tt := range tests {
+		if got := trailingPath(tt.file, tt.n); got != tt.want {
+			t.Errorf("trailingPath(%q, %d) = %q want %q", tt.file, tt.n, got, tt.want)
+		}
+	}
+}
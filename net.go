@@ -0,0 +1,339 @@
+package golog
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// DefaultNetBatchCount is default maximum number of logs in a batch.
+	DefaultNetBatchCount = 100
+	// DefaultNetBatchBytes is default maximum bytes of a batch.
+	DefaultNetBatchBytes = 64 << 10
+	// DefaultNetBatchInterval is default interval to send a batch.
+	DefaultNetBatchInterval = time.Second
+	// DefaultNetBufferBytes is default maximum bytes of logs buffered during outages.
+	DefaultNetBufferBytes = 8 << 20
+	// DefaultNetMinBackoff is default minimum delay before reconnecting.
+	DefaultNetMinBackoff = 100 * time.Millisecond
+	// DefaultNetMaxBackoff is default maximum delay before reconnecting.
+	DefaultNetMaxBackoff = 30 * time.Second
+	// DefaultNetTimeout is default timeout of dialing and writing.
+	DefaultNetTimeout = 5 * time.Second
+
+	errNetBackoff = errors.New("golog: net: waiting to reconnect")
+	errNetClosed  = errors.New("golog: net: logger closed")
+)
+
+// NetOption is network logger option.
+type NetOption func(l *NetLogger)
+
+// NetFormat set the logger used to encode each log, default is NewJSONLogger.
+// The logger must write each log with a single Write call, so colorful term
+// logger is not supported since it writes colors separately.
+func NetFormat(newLogger func(w io.Writer) Logger) NetOption {
+	return func(l *NetLogger) {
+		l.logger = newLogger(netWriter{l})
+	}
+}
+
+// NetFormatStd set the format of logs to the standard logger with opts.
+func NetFormatStd(opts ...StdOption) NetOption {
+	return NetFormat(func(w io.Writer) Logger {
+		return NewStdLogger(w, opts...)
+	})
+}
+
+// NetFormatTerm set the format of logs to the term logger without color with opts.
+func NetFormatTerm(opts ...TermOption) NetOption {
+	return NetFormat(func(w io.Writer) Logger {
+		return NewTermLogger(w, false, opts...)
+	})
+}
+
+// NetBatch set the maximum number of logs and bytes in a batch and the interval
+// to send a batch, a batch is sent as soon as either limit is reached.
+// DefaultNetBatchInterval is used if interval is not positive.
+func NetBatch(count, bytes int, interval time.Duration) NetOption {
+	return func(l *NetLogger) {
+		l.batchCount = count
+		l.batchBytes = bytes
+		l.interval = interval
+	}
+}
+
+// NetBuffer set the maximum bytes of logs buffered while the server is
+// unreachable, the oldest logs are dropped when it is exceeded.
+func NetBuffer(bytes int) NetOption {
+	return func(l *NetLogger) {
+		l.bufferBytes = bytes
+	}
+}
+
+// NetBackoff set the minimum and maximum delay before reconnecting,
+// the delay doubles after each failure.
+func NetBackoff(min, max time.Duration) NetOption {
+	return func(l *NetLogger) {
+		l.minBackoff = min
+		l.maxBackoff = max
+	}
+}
+
+// NetLogger is a logger which sends logs to a server in batches,
+// such as a local Fluent Bit or Vector agent.
+type NetLogger struct {
+	network     string
+	addr        string
+	logger      Logger
+	batchCount  int
+	batchBytes  int
+	interval    time.Duration
+	bufferBytes int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	mu      sync.Mutex
+	records [][]byte
+	size    int
+	closed  bool
+	dropped uint64
+
+	sendMu   sync.Mutex
+	conn     net.Conn
+	backoff  time.Duration
+	nextDial time.Time
+
+	flushCh chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+var _ Logger = (*NetLogger)(nil)
+
+// NewNetLogger new a logger which sends logs to addr.
+//
+// Network is one of "tcp", "udp" and "unix", each log is sent as a datagram
+// on packet connections. The connection is established lazily and
+// re-established with exponential backoff, logs are buffered in memory
+// in the meantime.
+func NewNetLogger(network, addr string, opts ...NetOption) *NetLogger {
+	l := &NetLogger{
+		network:     network,
+		addr:        addr,
+		batchCount:  DefaultNetBatchCount,
+		batchBytes:  DefaultNetBatchBytes,
+		interval:    DefaultNetBatchInterval,
+		bufferBytes: DefaultNetBufferBytes,
+		minBackoff:  DefaultNetMinBackoff,
+		maxBackoff:  DefaultNetMaxBackoff,
+		flushCh:     make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	l.logger = NewJSONLogger(netWriter{l})
+	for _, o := range opts {
+		o(l)
+	}
+	if l.interval <= 0 {
+		l.interval = DefaultNetBatchInterval
+	}
+
+	l.wg.Add(1)
+	go l.run()
+	return l
+}
+
+// Log write the kv pairs log.
+func (l *NetLogger) Log(level Level, kvs ...interface{}) {
+	l.logger.Log(level, kvs...)
+}
+
+// Dropped returns the number of logs dropped since the buffer is full.
+func (l *NetLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// Flush sends all the buffered logs.
+func (l *NetLogger) Flush() error {
+	return l.send(true)
+}
+
+// Sync sends all the buffered logs.
+func (l *NetLogger) Sync() error {
+	return l.Flush()
+}
+
+// Close flushes the buffered logs and closes the connection.
+func (l *NetLogger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return errNetClosed
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	close(l.done)
+	l.wg.Wait()
+	err := l.send(true)
+
+	l.sendMu.Lock()
+	defer l.sendMu.Unlock()
+	if l.conn != nil {
+		if cerr := l.conn.Close(); err == nil {
+			err = cerr
+		}
+		l.conn = nil
+	}
+	return err
+}
+
+type netWriter struct {
+	l *NetLogger
+}
+
+func (w netWriter) Write(p []byte) (int, error) {
+	w.l.enqueue(append([]byte(nil), p...))
+	return len(p), nil
+}
+
+func (l *NetLogger) enqueue(record []byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed || len(record) > l.bufferBytes {
+		atomic.AddUint64(&l.dropped, 1)
+		return
+	}
+	l.records = append(l.records, record)
+	l.size += len(record)
+	l.dropOldest()
+	if len(l.records) >= l.batchCount || l.size >= l.batchBytes {
+		select {
+		case l.flushCh <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// requeue put the unsent records back to the front of buffer,
+// the oldest records are dropped if the buffer is full.
+func (l *NetLogger) requeue(records [][]byte) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.records = append(records, l.records...)
+	l.size = 0
+	for _, r := range l.records {
+		l.size += len(r)
+	}
+	l.dropOldest()
+}
+
+// dropOldest drops the oldest records until the buffer is not full, l.mu must be held.
+func (l *NetLogger) dropOldest() {
+	for l.size > l.bufferBytes && len(l.records) > 0 {
+		l.size -= len(l.records[0])
+		l.records = l.records[1:]
+		atomic.AddUint64(&l.dropped, 1)
+	}
+}
+
+func (l *NetLogger) run() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		case <-l.flushCh:
+		}
+		_ = l.send(false)
+	}
+}
+
+// send sends the buffered records, it waits for backoff before reconnecting unless force is set.
+func (l *NetLogger) send(force bool) error {
+	l.sendMu.Lock()
+	defer l.sendMu.Unlock()
+
+	l.mu.Lock()
+	records := l.records
+	l.records, l.size = nil, 0
+	l.mu.Unlock()
+
+	for len(records) > 0 {
+		if err := l.connect(force); err != nil {
+			l.requeue(records)
+			return err
+		}
+
+		n := l.batchCount
+		if n <= 0 || n > len(records) {
+			n = len(records)
+		}
+		if err := l.write(records[:n]); err != nil {
+			_ = l.conn.Close()
+			l.conn = nil
+			l.requeue(records)
+			return err
+		}
+		records = records[n:]
+	}
+	return nil
+}
+
+func (l *NetLogger) connect(force bool) error {
+	if l.conn != nil {
+		return nil
+	}
+	if !force && time.Now().Before(l.nextDial) {
+		return errNetBackoff
+	}
+
+	conn, err := net.DialTimeout(l.network, l.addr, DefaultNetTimeout)
+	if err != nil {
+		if l.backoff *= 2; l.backoff < l.minBackoff {
+			l.backoff = l.minBackoff
+		} else if l.backoff > l.maxBackoff {
+			l.backoff = l.maxBackoff
+		}
+		l.nextDial = time.Now().Add(l.backoff)
+		return err
+	}
+	l.conn = conn
+	l.backoff = 0
+	return nil
+}
+
+func (l *NetLogger) write(records [][]byte) error {
+	_ = l.conn.SetWriteDeadline(time.Now().Add(DefaultNetTimeout))
+	if isPacketNetwork(l.network) {
+		for _, r := range records {
+			if _, err := l.conn.Write(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	buffers := net.Buffers(append([][]byte(nil), records...))
+	_, err := buffers.WriteTo(l.conn)
+	return err
+}
+
+// isPacketNetwork reports whether network is packet-oriented.
+func isPacketNetwork(network string) bool {
+	switch network {
+	case "udp", "udp4", "udp6", "unixgram":
+		return true
+	default:
+		return false
+	}
+}
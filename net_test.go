@@ -0,0 +1,189 @@
+package golog
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+)
+
+func acceptLines(t *testing.T, ln net.Listener, n int) []string {
+	t.Helper()
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("ln.Accept() error: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+
+	r := bufio.NewReader(conn)
+	lines := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("r.ReadString() error: %v", err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Test that NetLogger properly sends a batch when it is full.
+func TestNetLoggerBatchCount(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	l := NewNetLogger("tcp", ln.Addr().String(), NetBatch(2, 1<<10, time.Hour))
+	defer l.Close()
+
+	l.Log(LevelInfo, "k1", "v1")
+	l.Log(LevelWarn, "k2", "v2")
+	lines := acceptLines(t, ln, 2)
+	if got, want := lines[0], `{"level":"INFO","k1":"v1"}`+"\n"; got != want {
+		t.Errorf("lines[0] = %q want %q", got, want)
+	}
+	if got, want := lines[1], `{"level":"WARN","k2":"v2"}`+"\n"; got != want {
+		t.Errorf("lines[1] = %q want %q", got, want)
+	}
+}
+
+// Test that NetLogger properly sends a batch after interval.
+func TestNetLoggerBatchInterval(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	l := NewNetLogger("tcp", ln.Addr().String(),
+		NetBatch(100, 1<<10, 10*time.Millisecond),
+		NetFormatTerm(),
+	)
+	defer l.Close()
+
+	l.Log(LevelInfo, "k1", "v1")
+	if got, want := acceptLines(t, ln, 1)[0], "[INFO] k1:v1\n"; got != want {
+		t.Errorf("line = %q want %q", got, want)
+	}
+}
+
+// Test that NetFormatStd properly sets the format of logs.
+func TestNetLoggerFormatStd(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	l := NewNetLogger("tcp", ln.Addr().String(), NetBatch(1, 1<<10, time.Hour), NetFormatStd())
+	defer l.Close()
+
+	l.Log(LevelInfo, "k1", "v1")
+	if got, want := acceptLines(t, ln, 1)[0], `INFO, "k1": "v1"`+"\n"; got != want {
+		t.Errorf("line = %q want %q", got, want)
+	}
+}
+
+// Test that NetLogger uses default interval if batch interval is not positive.
+func TestNetLoggerBatchZeroInterval(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	l := NewNetLogger("tcp", ln.Addr().String(), NetBatch(1, 1<<10, 0))
+	defer l.Close()
+	if l.interval != DefaultNetBatchInterval {
+		t.Errorf("l.interval = %v want %v", l.interval, DefaultNetBatchInterval)
+	}
+
+	l.Log(LevelInfo, "k1", "v1")
+	if got, want := acceptLines(t, ln, 1)[0], `{"level":"INFO","k1":"v1"}`+"\n"; got != want {
+		t.Errorf("line = %q want %q", got, want)
+	}
+}
+
+// Test that NetLogger properly buffers logs during outages.
+func TestNetLoggerOutage(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	record := `{"level":"INFO","i":0}` + "\n"
+	l := NewNetLogger("tcp", addr,
+		NetBatch(100, 1<<10, time.Hour),
+		NetBuffer(3*len(record)),
+		NetBackoff(time.Millisecond, time.Millisecond),
+	)
+	defer l.Close()
+
+	for i := 0; i < 5; i++ {
+		l.Log(LevelInfo, "i", i)
+	}
+	if err := l.Flush(); err == nil {
+		t.Fatalf("l.Flush() without server succeed")
+	}
+	if got, want := l.Dropped(), uint64(2); got != want {
+		t.Errorf("l.Dropped() = %d want %d", got, want)
+	}
+
+	if ln, err = net.Listen("tcp", addr); err != nil {
+		t.Skipf("net.Listen() error: %v", err)
+	}
+	defer ln.Close()
+	if err := l.Flush(); err != nil {
+		t.Fatalf("l.Flush() error: %v", err)
+	}
+	// the oldest logs are dropped
+	for i, line := range acceptLines(t, ln, 3) {
+		if want := fmt.Sprintf(`{"level":"INFO","i":%d}`+"\n", i+2); line != want {
+			t.Errorf("lines[%d] = %q want %q", i, line, want)
+		}
+	}
+}
+
+// Test that NetLogger properly sends each log as a datagram over UDP.
+func TestNetLoggerUDP(t *testing.T) {
+	t.Parallel()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.ListenPacket() error: %v", err)
+	}
+	defer conn.Close()
+
+	l := NewNetLogger("udp", conn.LocalAddr().String())
+	l.Log(LevelInfo, "k1", "v1")
+	l.Log(LevelInfo, "k2", "v2")
+	if err := l.Close(); err != nil {
+		t.Fatalf("l.Close() error: %v", err)
+	}
+
+	for _, want := range []string{`{"level":"INFO","k1":"v1"}` + "\n", `{"level":"INFO","k2":"v2"}` + "\n"} {
+		if got := readPacket(t, conn); got != want {
+			t.Errorf("packet = %q want %q", got, want)
+		}
+	}
+	if err := l.Close(); err == nil {
+		t.Errorf("l.Close() twice succeed")
+	}
+}
@@ -0,0 +1,215 @@
+package otelgolog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kibaamor/golog"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+// DefaultScopeName is default instrumentation scope name of bridged logs.
+var DefaultScopeName = "github.com/kibaamor/golog/otelgolog"
+
+// LoggerOption is bridge logger option.
+type LoggerOption func(l *Logger)
+
+// LoggerProvider set the provider of OTel logger, default is the global one.
+// The resource of logs is configured on provider, such as sdklog.WithResource.
+func LoggerProvider(provider log.LoggerProvider) LoggerOption {
+	return func(l *Logger) {
+		l.provider = provider
+	}
+}
+
+// Scope set the instrumentation scope name and options, such as
+// log.WithInstrumentationVersion, default scope name is DefaultScopeName.
+func Scope(name string, opts ...log.LoggerOption) LoggerOption {
+	return func(l *Logger) {
+		l.scopeName = name
+		l.scopeOpts = opts
+	}
+}
+
+// TimestampFormat set the format to parse timestamp appended by
+// golog.HandlerTimestamp, default is golog.DefaultTimestampFormat.
+func TimestampFormat(format string) LoggerOption {
+	return func(l *Logger) {
+		l.timestampFormat = format
+	}
+}
+
+// Logger is a golog.Logger which converts logs into OTel log records.
+//
+// The level is converted to SeverityNumber and SeverityText, the message
+// is converted to Body, the timestamp is converted to Timestamp and
+// other kv pairs are converted to typed Attributes.
+type Logger struct {
+	provider        log.LoggerProvider
+	scopeName       string
+	scopeOpts       []log.LoggerOption
+	timestampFormat string
+	logger          log.Logger
+	ctx             context.Context
+}
+
+var _ golog.Logger = (*Logger)(nil)
+
+// NewLogger new a logger which emits logs through OTel logs pipeline.
+func NewLogger(opts ...LoggerOption) *Logger {
+	l := &Logger{
+		scopeName:       DefaultScopeName,
+		timestampFormat: golog.DefaultTimestampFormat,
+		ctx:             context.Background(),
+	}
+	for _, o := range opts {
+		o(l)
+	}
+	if l.provider == nil {
+		l.provider = global.GetLoggerProvider()
+	}
+	l.logger = l.provider.Logger(l.scopeName, l.scopeOpts...)
+	return l
+}
+
+// WithContext returns a copy of logger which emits logs with ctx,
+// so the records are correlated with the span in ctx.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	c := *l
+	c.ctx = ctx
+	return &c
+}
+
+// Severity convert log level to OTel severity.
+func Severity(level golog.Level) log.Severity {
+	switch {
+	case level <= golog.LevelDebug:
+		return log.SeverityDebug
+	case level == golog.LevelInfo:
+		return log.SeverityInfo
+	case level == golog.LevelWarn:
+		return log.SeverityWarn
+	case level == golog.LevelError:
+		return log.SeverityError
+	default:
+		return log.SeverityFatal
+	}
+}
+
+// Log emit the kv pairs log as an OTel log record.
+func (l *Logger) Log(level golog.Level, kvs ...interface{}) {
+	if len(kvs) == 0 {
+		return
+	}
+
+	kvs = golog.HandlerKeyPolicy(golog.DefaultKeyPolicy)(level, kvs)
+
+	severity := Severity(level)
+	if !l.logger.Enabled(l.ctx, log.EnabledParameters{Severity: severity}) {
+		return
+	}
+
+	var r log.Record
+	r.SetSeverity(severity)
+	r.SetSeverityText(level.String())
+	r.SetObservedTimestamp(time.Now())
+	for i := 0; i < len(kvs); i += 2 {
+		key := fmt.Sprint(kvs[i])
+		switch key {
+		case golog.DefaultMsgKey:
+			r.SetBody(Value(kvs[i+1]))
+			continue
+		case golog.DefaultTimestampKeyName:
+			if ts, ok := l.timestamp(kvs[i+1]); ok {
+				r.SetTimestamp(ts)
+				continue
+			}
+		}
+		r.AddAttributes(log.KeyValue{Key: key, Value: Value(kvs[i+1])})
+	}
+	l.logger.Emit(l.ctx, r)
+}
+
+func (l *Logger) timestamp(v interface{}) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		ts, err := time.Parse(l.timestampFormat, v)
+		return ts, err == nil
+	default:
+		return time.Time{}, false
+	}
+}
+
+// Value convert value into OTel log value, values of unsupported types
+// are formatted with fmt.Sprint.
+func Value(value interface{}) log.Value {
+	if v, ok := numberValue(value); ok {
+		return v
+	}
+	switch v := value.(type) {
+	case nil:
+		return log.Value{}
+	case string:
+		return log.StringValue(v)
+	case bool:
+		return log.BoolValue(v)
+	case []byte:
+		return log.BytesValue(v)
+	case []string:
+		vs := make([]log.Value, len(v))
+		for i, s := range v {
+			vs[i] = log.StringValue(s)
+		}
+		return log.SliceValue(vs...)
+	case []interface{}:
+		vs := make([]log.Value, len(v))
+		for i, e := range v {
+			vs[i] = Value(e)
+		}
+		return log.SliceValue(vs...)
+	case map[string]interface{}:
+		kvs := make([]log.KeyValue, 0, len(v))
+		for k, e := range v {
+			kvs = append(kvs, log.KeyValue{Key: k, Value: Value(e)})
+		}
+		return log.MapValue(kvs...)
+	case error:
+		return log.StringValue(v.Error())
+	case fmt.Stringer:
+		return log.StringValue(v.String())
+	default:
+		return log.StringValue(fmt.Sprint(v))
+	}
+}
+
+// numberValue convert value into OTel log value, ok is false if value is not a number.
+func numberValue(value interface{}) (v log.Value, ok bool) {
+	switch n := value.(type) {
+	case int:
+		return log.IntValue(n), true
+	case int8:
+		return log.Int64Value(int64(n)), true
+	case int16:
+		return log.Int64Value(int64(n)), true
+	case int32:
+		return log.Int64Value(int64(n)), true
+	case int64:
+		return log.Int64Value(n), true
+	case uint8:
+		return log.Int64Value(int64(n)), true
+	case uint16:
+		return log.Int64Value(int64(n)), true
+	case uint32:
+		return log.Int64Value(int64(n)), true
+	case float32:
+		return log.Float64Value(float64(n)), true
+	case float64:
+		return log.Float64Value(n), true
+	default:
+		return log.Value{}, false
+	}
+}
@@ -0,0 +1,117 @@
+package otelgolog
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kibaamor/golog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// memoryExporter is an in-memory log exporter.
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// Test that Logger properly converts logs into OTel log records.
+func TestLogger(t *testing.T) {
+	t.Parallel()
+
+	exporter := &memoryExporter{}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)),
+		sdklog.WithResource(resource.NewSchemaless(attribute.String("service.name", "svc"))),
+	)
+	logger := NewLogger(LoggerProvider(provider), Scope("scope", log.WithInstrumentationVersion("v1")))
+
+	now := time.Date(2022, 10, 28, 16, 37, 50, 786000000, time.UTC)
+	glogger := golog.WithHandler(logger, golog.HandlerTimestamp(golog.DefaultTimestampKeyName,
+		golog.DefaultTimestampFormat, func() time.Time { return now }))
+	glogger.Log(golog.LevelWarn, golog.DefaultMsgKey, "hello", "k1", 1, "k2", true, "k3", []string{"a"})
+
+	if len(exporter.records) != 1 {
+		t.Fatalf("len(records) = %d want 1", len(exporter.records))
+	}
+	r := exporter.records[0]
+	if got, want := r.Severity(), log.SeverityWarn; got != want {
+		t.Errorf("Severity() = %v want %v", got, want)
+	}
+	if got, want := r.SeverityText(), "WARN"; got != want {
+		t.Errorf("SeverityText() = %q want %q", got, want)
+	}
+	if got, want := r.Body(), log.StringValue("hello"); !got.Equal(want) {
+		t.Errorf("Body() = %v want %v", got, want)
+	}
+	if got := r.Timestamp(); !got.Equal(now) {
+		t.Errorf("Timestamp() = %v want %v", got, now)
+	}
+	if got, want := r.InstrumentationScope().Name, "scope"; got != want {
+		t.Errorf("InstrumentationScope().Name = %q want %q", got, want)
+	}
+	if got, want := r.InstrumentationScope().Version, "v1"; got != want {
+		t.Errorf("InstrumentationScope().Version = %q want %q", got, want)
+	}
+	if v, ok := r.Resource().Set().Value("service.name"); !ok || v.AsString() != "svc" {
+		t.Errorf("Resource() service.name = %v want svc", v)
+	}
+
+	want := []log.KeyValue{
+		log.Int64("k1", 1),
+		log.Bool("k2", true),
+		log.Slice("k3", log.StringValue("a")),
+	}
+	var got []log.KeyValue
+	r.WalkAttributes(func(kv log.KeyValue) bool {
+		got = append(got, kv)
+		return true
+	})
+	if len(got) != len(want) {
+		t.Fatalf("attributes = %v want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("attributes[%d] = %v want %v", i, got[i], want[i])
+		}
+	}
+}
+
+// Test that Severity properly convert level to severity.
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+
+	want := map[golog.Level]log.Severity{
+		golog.LevelDebug: log.SeverityDebug,
+		golog.LevelInfo:  log.SeverityInfo,
+		golog.LevelWarn:  log.SeverityWarn,
+		golog.LevelError: log.SeverityError,
+		golog.LevelFatal: log.SeverityFatal,
+	}
+	for level, severity := range want {
+		if got := Severity(level); got != severity {
+			t.Errorf("Severity(%v) = %v want %v", level, got, severity)
+		}
+	}
+}
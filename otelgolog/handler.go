@@ -0,0 +1,161 @@
+// Package otelgolog integrates golog with OpenTelemetry.
+package otelgolog
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kibaamor/golog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// DefaultTraceIDKey is default log key for trace id.
+	DefaultTraceIDKey = "trace_id"
+	// DefaultSpanIDKey is default log key for span id.
+	DefaultSpanIDKey = "span_id"
+	// DefaultTraceFlagsKey is default log key for trace flags.
+	DefaultTraceFlagsKey = "trace_flags"
+	// DefaultSpanEventName is default name of span events recorded from logs.
+	DefaultSpanEventName = "log"
+)
+
+// Option is trace handler option.
+type Option func(h *handler)
+
+// TraceKeys set log keys for trace id, span id and trace flags.
+func TraceKeys(traceIDKey, spanIDKey, traceFlagsKey string) Option {
+	return func(h *handler) {
+		h.traceIDKey = traceIDKey
+		h.spanIDKey = spanIDKey
+		h.traceFlagsKey = traceFlagsKey
+	}
+}
+
+// SpanEvents record logs with level greater than or equal to level as
+// events of the span in context.
+func SpanEvents(level golog.Level) Option {
+	return func(h *handler) {
+		h.events = true
+		h.eventLevel = level
+	}
+}
+
+type handler struct {
+	traceIDKey    string
+	spanIDKey     string
+	traceFlagsKey string
+	events        bool
+	eventLevel    golog.Level
+}
+
+// Handler returns a handler which appends trace id, span id and trace flags
+// of the span in ctx into log. Logs are kept as is if ctx has no valid span.
+//
+// Since handler is bound to ctx, it is usually created per request:
+//
+//	logger := golog.WithHandler(base, otelgolog.Handler(ctx))
+func Handler(ctx context.Context, opts ...Option) golog.Handler {
+	h := &handler{
+		traceIDKey:    DefaultTraceIDKey,
+		spanIDKey:     DefaultSpanIDKey,
+		traceFlagsKey: DefaultTraceFlagsKey,
+	}
+	for _, o := range opts {
+		o(h)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	return func(level golog.Level, kvs []interface{}) []interface{} {
+		sc := span.SpanContext()
+		if !sc.IsValid() {
+			return kvs
+		}
+		if h.events && level >= h.eventLevel && span.IsRecording() {
+			attrs := append(Attributes(kvs), attribute.String("level", level.String()))
+			span.AddEvent(DefaultSpanEventName, trace.WithAttributes(attrs...))
+		}
+		return append(kvs,
+			h.traceIDKey, sc.TraceID().String(),
+			h.spanIDKey, sc.SpanID().String(),
+			h.traceFlagsKey, sc.TraceFlags().String(),
+		)
+	}
+}
+
+// WithContext decorate logger with Handler of ctx.
+func WithContext(ctx context.Context, logger golog.Logger, opts ...Option) golog.Logger {
+	return golog.WithHandler(logger, Handler(ctx, opts...))
+}
+
+// Attributes convert kv pairs into attributes, values of unsupported types
+// are formatted with fmt.Sprint.
+func Attributes(kvs []interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, (len(kvs)+1)/2)
+	for i := 0; i < len(kvs); i += 2 {
+		key := fmt.Sprint(kvs[i])
+		if i+1 >= len(kvs) {
+			attrs = append(attrs, attribute.String(key, "KEY VALUES UNPAIRED"))
+			break
+		}
+		attrs = append(attrs, attributeOf(key, kvs[i+1]))
+	}
+	return attrs
+}
+
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	if kv, ok := numberAttribute(key, value); ok {
+		return kv
+	}
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case []string:
+		return attribute.StringSlice(key, v)
+	case []bool:
+		return attribute.BoolSlice(key, v)
+	case []int:
+		return attribute.IntSlice(key, v)
+	case []int64:
+		return attribute.Int64Slice(key, v)
+	case []float64:
+		return attribute.Float64Slice(key, v)
+	case error:
+		return attribute.String(key, v.Error())
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
+
+// numberAttribute convert value into attribute, ok is false if value is not a number.
+func numberAttribute(key string, value interface{}) (kv attribute.KeyValue, ok bool) {
+	switch v := value.(type) {
+	case int:
+		return attribute.Int(key, v), true
+	case int8:
+		return attribute.Int(key, int(v)), true
+	case int16:
+		return attribute.Int(key, int(v)), true
+	case int32:
+		return attribute.Int(key, int(v)), true
+	case int64:
+		return attribute.Int64(key, v), true
+	case uint8:
+		return attribute.Int(key, int(v)), true
+	case uint16:
+		return attribute.Int(key, int(v)), true
+	case uint32:
+		return attribute.Int64(key, int64(v)), true
+	case float32:
+		return attribute.Float64(key, float64(v)), true
+	case float64:
+		return attribute.Float64(key, v), true
+	default:
+		return attribute.KeyValue{}, false
+	}
+}
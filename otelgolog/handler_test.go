@@ -0,0 +1,70 @@
+package otelgolog
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/kibaamor/golog"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Test that Handler properly appends span context into log.
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, span := tp.Tracer("test").Start(context.Background(), "op")
+
+	var buf bytes.Buffer
+	logger := WithContext(ctx, golog.NewTermLogger(&buf, false), SpanEvents(golog.LevelWarn))
+	logger.Log(golog.LevelInfo, golog.DefaultMsgKey, "m1")
+	logger.Log(golog.LevelError, golog.DefaultMsgKey, "m2", "k1", 1)
+	span.End()
+
+	sc := span.SpanContext()
+	suffix := " trace_id:" + sc.TraceID().String() + " span_id:" + sc.SpanID().String() + " trace_flags:01\n"
+	want := "[INFO] m1" + suffix + "[ERROR] m2 k1:1" + suffix
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("len(spans) = %d want 1", len(spans))
+	}
+	events := spans[0].Events
+	if len(events) != 1 {
+		t.Fatalf("len(events) = %d want 1", len(events))
+	}
+	wantAttrs := []attribute.KeyValue{
+		attribute.String(golog.DefaultMsgKey, "m2"),
+		attribute.Int("k1", 1),
+		attribute.String("level", "ERROR"),
+	}
+	if got := events[0].Attributes; len(got) != len(wantAttrs) {
+		t.Fatalf("events[0].Attributes = %v want %v", got, wantAttrs)
+	}
+	for i, attr := range events[0].Attributes {
+		if attr != wantAttrs[i] {
+			t.Errorf("events[0].Attributes[%d] = %v want %v", i, attr, wantAttrs[i])
+		}
+	}
+}
+
+// Test that Handler keeps log as is without span.
+func TestHandlerWithoutSpan(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := golog.WithHandler(golog.NewTermLogger(&buf, false),
+		Handler(context.Background(), TraceKeys("tid", "sid", "flags")))
+	logger.Log(golog.LevelInfo, golog.DefaultMsgKey, "m1")
+
+	if got, want := buf.String(), "[INFO] m1\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
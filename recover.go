@@ -0,0 +1,56 @@
+package golog
+
+import "runtime/debug"
+
+// DefaultRecoverMsg is default message of logs of recovered panics.
+var DefaultRecoverMsg = "golog: panic recovered"
+
+type recoverConfig struct {
+	repanic bool
+}
+
+// RecoverOption is option for RecoverAndLog and Go.
+type RecoverOption func(c *recoverConfig)
+
+// RecoverRepanic panics again with the same value after the panic is logged,
+// so the program still crashes.
+func RecoverRepanic() RecoverOption {
+	return func(c *recoverConfig) {
+		c.repanic = true
+	}
+}
+
+// RecoverAndLog recovers the panic and logs its value and stack at fatal level,
+// then syncs logger. It must be called directly by defer:
+//
+//	defer golog.RecoverAndLog(logger)
+func RecoverAndLog(logger Logger, opts ...RecoverOption) {
+	r := recover()
+	if r == nil {
+		return
+	}
+
+	c := &recoverConfig{}
+	for _, o := range opts {
+		o(c)
+	}
+
+	logger.Log(LevelFatal,
+		DefaultMsgKey, DefaultRecoverMsg,
+		"panic", r,
+		"stack", string(debug.Stack()),
+	)
+	_ = Sync(logger)
+
+	if c.repanic {
+		panic(r)
+	}
+}
+
+// Go runs fn in a new goroutine guarded by RecoverAndLog.
+func Go(logger Logger, fn func(), opts ...RecoverOption) {
+	go func() {
+		defer RecoverAndLog(logger, opts...)
+		fn()
+	}()
+}
@@ -0,0 +1,70 @@
+package golog
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Test that RecoverAndLog properly logs panics.
+func TestRecoverAndLog(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	l := NewStdLogger(buf)
+	func() {
+		defer RecoverAndLog(l)
+	}()
+	if got := buf.String(); got != "" {
+		t.Errorf("buf.String() = %q want empty without panic", got)
+	}
+
+	func() {
+		defer RecoverAndLog(l)
+		panic("boom")
+	}()
+	got := buf.String()
+	if want := `FATAL, "msg": "golog: panic recovered", "panic": "boom", "stack": "`; !strings.HasPrefix(got, want) {
+		t.Errorf("buf.String() = %q want prefix %q", got, want)
+	}
+	if !strings.Contains(got, "recover_test.go") {
+		t.Errorf("buf.String() = %q want stack", got)
+	}
+}
+
+// Test that RecoverRepanic properly panics again.
+func TestRecoverRepanic(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("recover() = %v want boom", r)
+		}
+		if !strings.HasPrefix(buf.String(), "FATAL") {
+			t.Errorf("buf.String() = %q want panic logged", buf.String())
+		}
+	}()
+
+	defer RecoverAndLog(NewStdLogger(buf), RecoverRepanic())
+	panic("boom")
+}
+
+// Test that Go properly recovers panics of goroutine.
+func TestGo(t *testing.T) {
+	t.Parallel()
+
+	buf := &syncBuffer{}
+	Go(NewStdLogger(buf), func() {
+		panic("boom")
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for buf.String() == "" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := buf.String(); !strings.Contains(got, `"panic": "boom"`) {
+		t.Errorf("buf.String() = %q want panic logged", got)
+	}
+}
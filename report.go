@@ -0,0 +1,58 @@
+package golog
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultErrorReportInterval is default minimum interval between reports of
+// errors of loggers which send logs, such as JournaldLogger and SyslogLogger.
+var DefaultErrorReportInterval = 10 * time.Second
+
+// errorReporter reports errors of a logger to fallback, errors within
+// DefaultErrorReportInterval after a report are counted and the count is
+// reported with the next error.
+type errorReporter struct {
+	name     string
+	fallback Logger
+	now      func() time.Time
+
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+}
+
+// newErrorReporter new a reporter of errors of logger name, errors are
+// reported to standard error if fallback is nil.
+func newErrorReporter(name string, fallback Logger) *errorReporter {
+	if fallback == nil {
+		fallback = NewStdLogger(os.Stderr)
+	}
+	return &errorReporter{name: name, fallback: fallback, now: time.Now}
+}
+
+// report reports err to fallback unless an error is reported recently,
+// fallback is called without holding any lock, so it may log to the logger.
+func (r *errorReporter) report(err error) {
+	now := r.now()
+	r.mu.Lock()
+	if !r.last.IsZero() && now.Sub(r.last) < DefaultErrorReportInterval {
+		r.suppressed++
+		r.mu.Unlock()
+		return
+	}
+	r.last = now
+	suppressed := r.suppressed
+	r.suppressed = 0
+	r.mu.Unlock()
+
+	kvs := []interface{}{
+		DefaultMsgKey, "golog: " + r.name + " error",
+		"error", err.Error(),
+	}
+	if suppressed > 0 {
+		kvs = append(kvs, "suppressed", suppressed)
+	}
+	r.fallback.Log(LevelError, kvs...)
+}
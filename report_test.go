@@ -0,0 +1,33 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// Test that errorReporter properly limits the rate of reports.
+func TestErrorReporter(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	r := newErrorReporter("test", NewStdLogger(buf))
+	now := time.Date(2022, 10, 28, 16, 37, 50, 0, time.UTC)
+	r.now = func() time.Time {
+		return now
+	}
+
+	r.report(errors.New("e1"))
+	r.report(errors.New("e2"))
+	r.report(errors.New("e3"))
+	now = now.Add(DefaultErrorReportInterval)
+	r.report(errors.New("e4"))
+
+	want := `ERROR, "msg": "golog: test error", "error": "e1"
+ERROR, "msg": "golog: test error", "error": "e4", "suppressed": "2"
+`
+	if got := buf.String(); got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readFile(t *testing.T, filename string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("os.ReadFile(%q) error: %v", filename, err)
+	}
+	return string(data)
+}
+
+// Test that RotateWriter properly rotates files.
+func TestRotateWriter(t *testing.T) {
+	t.Parallel()
+
+	filename := filepath.Join(t.TempDir(), "test.log")
+	w, err := NewRotateWriter(filename, 10, 2)
+	if err != nil {
+		t.Fatalf("NewRotateWriter() error: %v", err)
+	}
+
+	for _, s := range []string{"aaaaaa\n", "bbbbbb\n", "cccccc\n", "dddddd\n"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf("w.Write() error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("w.Close() error: %v", err)
+	}
+
+	if got, want := readFile(t, filename), "dddddd\n"; got != want {
+		t.Errorf("file = %q want %q", got, want)
+	}
+	if got, want := readFile(t, filename+".1"), "cccccc\n"; got != want {
+		t.Errorf("file.1 = %q want %q", got, want)
+	}
+	if got, want := readFile(t, filename+".2"), "bbbbbb\n"; got != want {
+		t.Errorf("file.2 = %q want %q", got, want)
+	}
+	if _, err := os.Stat(filename + ".3"); !os.IsNotExist(err) {
+		t.Errorf("file.3 exists")
+	}
+	if _, err := w.Write([]byte("x")); err == nil {
+		t.Errorf("w.Write() after Close() succeed")
+	}
+}
@@ -0,0 +1,66 @@
+package golog
+
+// RouteRule is a routing rule used by Route and RouteAll.
+type RouteRule struct {
+	filter Filter
+	logger Logger
+}
+
+// When create a rule which routes logs not discarded by filter to logger.
+func When(filter Filter, logger Logger) RouteRule {
+	return RouteRule{filter: filter, logger: logger}
+}
+
+// Default create a rule which routes all logs to logger.
+func Default(logger Logger) RouteRule {
+	return RouteRule{logger: logger}
+}
+
+func (r *RouteRule) match(level Level, kvs []interface{}) bool {
+	return r.filter == nil || !r.filter(level, kvs)
+}
+
+type routeLogger struct {
+	rules  []RouteRule
+	fanout bool
+}
+
+func (l *routeLogger) Log(level Level, kvs ...interface{}) {
+	for i := range l.rules {
+		r := &l.rules[i]
+		if !r.match(level, kvs) {
+			continue
+		}
+		r.logger.Log(level, kvs...)
+		if !l.fanout {
+			return
+		}
+	}
+}
+
+// Sync flushes buffered logs of the loggers of all rules.
+func (l *routeLogger) Sync() error {
+	errs := make([]error, 0, len(l.rules))
+	for i := range l.rules {
+		errs = append(errs, Sync(l.rules[i].logger))
+	}
+	return joinErrors(errs...)
+}
+
+var _ Logger = (*routeLogger)(nil)
+
+// Route creates a logger that routes each log to the logger of
+// the first matched rule, logs matched by no rule are discarded.
+//
+// Default rule matches everything, so it should be the last rule.
+func Route(rules ...RouteRule) Logger {
+	return &routeLogger{rules: append([]RouteRule(nil), rules...)}
+}
+
+// RouteAll creates a logger that routes each log to the loggers of
+// all matched rules, similar to MultiLogger with per-logger filter.
+//
+// Each rule's filter is checked once per log.
+func RouteAll(rules ...RouteRule) Logger {
+	return &routeLogger{rules: append([]RouteRule(nil), rules...), fanout: true}
+}
@@ -0,0 +1,103 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Test that Route properly routes log to the first matched rule.
+func TestRoute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		l       Level
+		wantErr string
+		wantOut string
+	}{
+		{
+			name:    "INFO",
+			l:       LevelInfo,
+			wantErr: "",
+			wantOut: `INFO, "k1": "v1"` + "\n",
+		},
+		{
+			name:    "ERROR",
+			l:       LevelError,
+			wantErr: `ERROR, "k1": "v1"` + "\n",
+			wantOut: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errBuf, outBuf bytes.Buffer
+			log := Route(
+				When(FilterLevel(LevelError), NewStdLogger(&errBuf)),
+				Default(NewStdLogger(&outBuf)),
+			)
+
+			log.Log(tt.l, "k1", "v1")
+			if got := errBuf.String(); got != tt.wantErr {
+				t.Errorf("errBuf.String() = %q want = %q", got, tt.wantErr)
+			}
+			if got := outBuf.String(); got != tt.wantOut {
+				t.Errorf("outBuf.String() = %q want = %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+// Test that RouteAll properly routes log to all matched rules.
+func TestRouteAll(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		l       Level
+		wantErr string
+		wantOut string
+	}{
+		{
+			name:    "INFO",
+			l:       LevelInfo,
+			wantErr: "",
+			wantOut: `INFO, "k1": "v1"` + "\n",
+		},
+		{
+			name:    "ERROR",
+			l:       LevelError,
+			wantErr: `ERROR, "k1": "v1"` + "\n",
+			wantOut: `ERROR, "k1": "v1"` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var errBuf, outBuf bytes.Buffer
+			log := RouteAll(
+				When(FilterLevel(LevelError), NewStdLogger(&errBuf)),
+				Default(NewStdLogger(&outBuf)),
+			)
+
+			log.Log(tt.l, "k1", "v1")
+			if got := errBuf.String(); got != tt.wantErr {
+				t.Errorf("errBuf.String() = %q want = %q", got, tt.wantErr)
+			}
+			if got := outBuf.String(); got != tt.wantOut {
+				t.Errorf("outBuf.String() = %q want = %q", got, tt.wantOut)
+			}
+		})
+	}
+}
+
+// Test that Route discards log matched by no rule.
+func TestRouteNoMatch(t *testing.T) {
+	var buf bytes.Buffer
+	log := Route(When(FilterLevel(LevelError), NewStdLogger(&buf)))
+
+	log.Log(LevelInfo, "k1", "v1")
+	if got := buf.String(); got != "" {
+		t.Errorf("buf.String() = %q want = %q", got, "")
+	}
+}
@@ -0,0 +1,64 @@
+package golog
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+type sampleKey struct {
+	level Level
+	msg   string
+}
+
+type sampler struct {
+	mu         sync.Mutex
+	tick       time.Duration
+	first      int
+	thereafter int
+	start      time.Time
+	counts     map[sampleKey]int
+	now        func() time.Time
+}
+
+func (s *sampler) discard(level Level, kvs []interface{}) bool {
+	key := sampleKey{level: level}
+	for i := 0; i+1 < len(kvs); i += 2 {
+		if k, ok := kvs[i].(string); ok && k == DefaultMsgKey {
+			key.msg = fmt.Sprint(kvs[i+1])
+			break
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if now.Sub(s.start) >= s.tick {
+		s.start = now
+		s.counts = make(map[sampleKey]int)
+	}
+	s.counts[key]++
+	n := s.counts[key]
+	if n <= s.first {
+		return false
+	}
+	return s.thereafter <= 0 || (n-s.first)%s.thereafter != 0
+}
+
+// FilterSample sample logs with the same level and message: within each tick,
+// the first logs are kept, then every thereafter-th log is kept.
+// All logs after first are discarded if thereafter is not positive.
+func FilterSample(tick time.Duration, first, thereafter int) Filter {
+	return newSampler(tick, first, thereafter, time.Now).discard
+}
+
+func newSampler(tick time.Duration, first, thereafter int, now func() time.Time) *sampler {
+	return &sampler{
+		tick:       tick,
+		first:      first,
+		thereafter: thereafter,
+		counts:     make(map[sampleKey]int),
+		now:        now,
+	}
+}
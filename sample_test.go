@@ -0,0 +1,37 @@
+package golog
+
+import (
+	"testing"
+	"time"
+)
+
+// Test that FilterSample properly sample logs.
+func TestFilterSample(t *testing.T) {
+	now := time.Now()
+	f := newSampler(time.Second, 2, 3, func() time.Time {
+		return now
+	}).discard
+	kvs := []interface{}{DefaultMsgKey, "m1"}
+
+	var kept []int
+	for i := 1; i <= 10; i++ {
+		if !f(LevelInfo, kvs) {
+			kept = append(kept, i)
+		}
+	}
+	if got, want := len(kept), 4; got != want {
+		t.Fatalf("kept = %v want 4 logs", kept)
+	}
+	if kept[2] != 5 || kept[3] != 8 {
+		t.Errorf("kept = %v want [1 2 5 8]", kept)
+	}
+
+	if f(LevelInfo, []interface{}{DefaultMsgKey, "m2"}) {
+		t.Errorf("log with different message discarded")
+	}
+
+	now = now.Add(time.Second)
+	if f(LevelInfo, kvs) {
+		t.Errorf("log in next tick discarded")
+	}
+}
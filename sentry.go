@@ -0,0 +1,559 @@
+package golog
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// DefaultSentryQueueSize is default maximum number of events waiting to be sent.
+	DefaultSentryQueueSize = 1000
+	// DefaultSentryRateLimit is default maximum number of events in DefaultSentryRateInterval.
+	DefaultSentryRateLimit = 100
+	// DefaultSentryRateInterval is default interval of rate limiting.
+	DefaultSentryRateInterval = time.Minute
+
+	errSentryClosed = errors.New("golog: sentry: logger closed")
+)
+
+// SentryOption is Sentry logger option.
+type SentryOption func(l *SentryLogger)
+
+// SentryLevel set the minimum level of logs to report, default is LevelError.
+func SentryLevel(level Level) SentryOption {
+	return func(l *SentryLogger) {
+		l.level = level
+	}
+}
+
+// SentryTagKeys set keys of kvs reported as tags, other kvs are reported as extra.
+func SentryTagKeys(keys ...string) SentryOption {
+	return func(l *SentryLogger) {
+		l.tagKeys = keys
+	}
+}
+
+// SentryEnvironment set the environment of events.
+func SentryEnvironment(env string) SentryOption {
+	return func(l *SentryLogger) {
+		l.environment = env
+	}
+}
+
+// SentryRelease set the release of events.
+func SentryRelease(release string) SentryOption {
+	return func(l *SentryLogger) {
+		l.release = release
+	}
+}
+
+// SentryClient set the client to send events, default is http.DefaultClient.
+func SentryClient(client *http.Client) SentryOption {
+	return func(l *SentryLogger) {
+		l.client = client
+	}
+}
+
+// SentryQueue set the maximum number of events waiting to be sent,
+// new events are dropped when it is exceeded.
+func SentryQueue(size int) SentryOption {
+	return func(l *SentryLogger) {
+		l.queueSize = size
+	}
+}
+
+// SentryRateLimit set the maximum number of events reported in each interval,
+// events exceed the limit are dropped. Rate limits responded by Sentry are
+// always respected.
+func SentryRateLimit(count int, interval time.Duration) SentryOption {
+	return func(l *SentryLogger) {
+		l.rateCount = count
+		l.rateInterval = interval
+	}
+}
+
+// SentryFallback set the logger to report errors of encoding events and
+// sending them in background, default is standard error. Errors are reported
+// at most once in DefaultErrorReportInterval.
+func SentryFallback(fallback Logger) SentryOption {
+	return func(l *SentryLogger) {
+		l.fallback = fallback
+	}
+}
+
+// SentryLogger is a logger which reports logs to Sentry as events.
+type SentryLogger struct {
+	dsn          string
+	url          string
+	auth         string
+	level        Level
+	tagKeys      []string
+	environment  string
+	release      string
+	serverName   string
+	client       *http.Client
+	queueSize    int
+	rateCount    int
+	rateInterval time.Duration
+	now          func() time.Time
+	fallback     Logger
+	reporter     *errorReporter
+
+	mu          sync.Mutex
+	events      []sentryEnvelope
+	closed      bool
+	rateStart   time.Time
+	rateN       int
+	limitedTill time.Time
+	dropped     uint64
+
+	sendMu  sync.Mutex
+	sendErr error
+	flushCh chan struct{}
+	done    chan struct{}
+	wg      sync.WaitGroup
+}
+
+var _ Logger = (*SentryLogger)(nil)
+
+type sentryEnvelope struct {
+	id      string
+	payload []byte
+}
+
+// NewSentryLogger new a logger which reports logs to Sentry project of dsn,
+// such as "https://<key>@sentry.example.com/<project>".
+func NewSentryLogger(dsn string, opts ...SentryOption) (*SentryLogger, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("golog: sentry: invalid dsn: %w", err)
+	}
+	i := strings.LastIndexByte(u.Path, '/')
+	if u.User == nil || u.User.Username() == "" || i < 0 || u.Path[i+1:] == "" {
+		return nil, fmt.Errorf("golog: sentry: invalid dsn %q", dsn)
+	}
+	key, project := u.User.Username(), u.Path[i+1:]
+	secret, _ := u.User.Password()
+
+	l := &SentryLogger{
+		dsn:          dsn,
+		url:          fmt.Sprintf("%s://%s%s/api/%s/envelope/", u.Scheme, u.Host, u.Path[:i], project),
+		auth:         "Sentry sentry_version=7, sentry_client=golog, sentry_key=" + key,
+		level:        LevelError,
+		client:       http.DefaultClient,
+		queueSize:    DefaultSentryQueueSize,
+		rateCount:    DefaultSentryRateLimit,
+		rateInterval: DefaultSentryRateInterval,
+		now:          time.Now,
+		flushCh:      make(chan struct{}, 1),
+		done:         make(chan struct{}),
+	}
+	if secret != "" {
+		l.auth += ", sentry_secret=" + secret
+	}
+	l.serverName, _ = os.Hostname()
+	for _, o := range opts {
+		o(l)
+	}
+	l.reporter = newErrorReporter("sentry", l.fallback)
+
+	l.wg.Add(1)
+	go l.run()
+	return l, nil
+}
+
+// Log reports the kv pairs log as an event if its level is high enough.
+func (l *SentryLogger) Log(level Level, kvs ...interface{}) {
+	if level < l.level || len(kvs) == 0 {
+		return
+	}
+
+	kvs = normalizeKVs(DefaultKeyPolicy, kvs)
+
+	now := l.now()
+	if !l.allow(now) {
+		atomic.AddUint64(&l.dropped, 1)
+		return
+	}
+
+	id := sentryEventID()
+	payload, err := json.Marshal(l.event(id, now, level, kvs))
+	if err != nil {
+		l.reporter.report(err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed || len(l.events) >= l.queueSize {
+		atomic.AddUint64(&l.dropped, 1)
+		return
+	}
+	l.events = append(l.events, sentryEnvelope{id: id, payload: payload})
+	select {
+	case l.flushCh <- struct{}{}:
+	default:
+	}
+}
+
+// allow reports whether an event can be reported now by rate limits.
+func (l *SentryLogger) allow(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Before(l.limitedTill) {
+		return false
+	}
+	if l.rateCount <= 0 {
+		return true
+	}
+	if now.Sub(l.rateStart) >= l.rateInterval {
+		l.rateStart = now
+		l.rateN = 0
+	}
+	l.rateN++
+	return l.rateN <= l.rateCount
+}
+
+type sentryFrame struct {
+	Function string `json:"function,omitempty"`
+	Module   string `json:"module,omitempty"`
+	Filename string `json:"filename,omitempty"`
+	AbsPath  string `json:"abs_path,omitempty"`
+	Lineno   int    `json:"lineno,omitempty"`
+	InApp    bool   `json:"in_app"`
+}
+
+type sentryStacktrace struct {
+	Frames []sentryFrame `json:"frames"`
+}
+
+type sentryException struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+type sentryExceptions struct {
+	Values []sentryException `json:"values"`
+}
+
+type sentryEvent struct {
+	EventID     string                     `json:"event_id"`
+	Timestamp   string                     `json:"timestamp"`
+	Level       string                     `json:"level"`
+	Platform    string                     `json:"platform"`
+	Logger      string                     `json:"logger"`
+	ServerName  string                     `json:"server_name,omitempty"`
+	Environment string                     `json:"environment,omitempty"`
+	Release     string                     `json:"release,omitempty"`
+	Message     string                     `json:"message,omitempty"`
+	Fingerprint []string                   `json:"fingerprint"`
+	Tags        map[string]string          `json:"tags,omitempty"`
+	Extra       map[string]json.RawMessage `json:"extra,omitempty"`
+	Exception   *sentryExceptions          `json:"exception,omitempty"`
+}
+
+func (l *SentryLogger) event(id string, now time.Time, level Level, kvs []interface{}) *sentryEvent {
+	e := &sentryEvent{
+		EventID:     id,
+		Timestamp:   now.UTC().Format(time.RFC3339Nano),
+		Level:       SentryLevelName(level),
+		Platform:    "go",
+		Logger:      "golog",
+		ServerName:  l.serverName,
+		Environment: l.environment,
+		Release:     l.release,
+	}
+
+	frames := sentryStack()
+	var caller string
+	msg, others := splitMsg(kvs)
+	if msg != nil {
+		e.Message = fmt.Sprint(msg)
+	}
+	for i := 0; i < len(others); i += 2 {
+		k, v := fmt.Sprint(others[i]), others[i+1]
+		if k == DefaultCallerKeyName {
+			caller = fmt.Sprint(v)
+		}
+		if err, ok := v.(error); ok && e.Exception == nil {
+			e.Exception = &sentryExceptions{Values: []sentryException{{
+				Type:       fmt.Sprintf("%T", err),
+				Value:      err.Error(),
+				Stacktrace: &sentryStacktrace{Frames: frames},
+			}}}
+			continue
+		}
+		if l.isTag(k) {
+			if e.Tags == nil {
+				e.Tags = make(map[string]string)
+			}
+			e.Tags[k] = fmt.Sprint(v)
+			continue
+		}
+		if e.Extra == nil {
+			e.Extra = make(map[string]json.RawMessage)
+		}
+		var buf bytes.Buffer
+		writeJSONValue(&buf, v)
+		e.Extra[k] = buf.Bytes()
+	}
+
+	if caller == "" && len(frames) > 0 {
+		f := frames[len(frames)-1]
+		caller = f.Filename + ":" + strconv.Itoa(f.Lineno)
+	}
+	e.Fingerprint = []string{e.Message, caller}
+	return e
+}
+
+func (l *SentryLogger) isTag(key string) bool {
+	for _, k := range l.tagKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SentryLevelName convert log level to Sentry level.
+func SentryLevelName(level Level) string {
+	switch {
+	case level <= LevelDebug:
+		return "debug"
+	case level == LevelInfo:
+		return "info"
+	case level == LevelWarn:
+		return "warning"
+	case level == LevelError:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+// sentryStack returns frames of the current goroutine in Sentry order,
+// the oldest frame first, frames of golog are skipped.
+func sentryStack() []sentryFrame {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	it := runtime.CallersFrames(pcs[:n])
+
+	var frames []sentryFrame
+	skip := true
+	for {
+		f, more := it.Next()
+		if skip && isPackageFile(f.File) {
+			if !more {
+				break
+			}
+			continue
+		}
+		skip = false
+
+		module, function := splitFuncName(f.Function)
+		frames = append(frames, sentryFrame{
+			Function: function,
+			Module:   module,
+			Filename: filepath.Base(f.File),
+			AbsPath:  f.File,
+			Lineno:   f.Line,
+			// packages of standard library have no dot in the first path element
+			InApp: strings.Contains(strings.SplitN(module, "/", 2)[0], "."),
+		})
+		if !more {
+			break
+		}
+	}
+
+	for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
+		frames[i], frames[j] = frames[j], frames[i]
+	}
+	return frames
+}
+
+// splitFuncName splits "github.com/a/b.(*T).F" into "github.com/a/b" and "(*T).F".
+func splitFuncName(name string) (module, function string) {
+	i := strings.LastIndexByte(name, '/')
+	if j := strings.IndexByte(name[i+1:], '.'); j >= 0 {
+		return name[:i+1+j], name[i+1+j+1:]
+	}
+	return "", name
+}
+
+func sentryEventID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// Dropped returns the number of events dropped since the queue is full,
+// rate limited or the request is failed.
+func (l *SentryLogger) Dropped() uint64 {
+	return atomic.LoadUint64(&l.dropped)
+}
+
+// Flush sends all the queued events, it returns the first error of sending
+// events since the last Flush, including events sent in background.
+func (l *SentryLogger) Flush() error {
+	l.sendMu.Lock()
+	defer l.sendMu.Unlock()
+
+	_ = l.send()
+	err := l.sendErr
+	l.sendErr = nil
+	return err
+}
+
+// Sync sends all the queued events.
+func (l *SentryLogger) Sync() error {
+	return l.Flush()
+}
+
+// Close flushes the queued events and stops the logger.
+func (l *SentryLogger) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return errSentryClosed
+	}
+	l.closed = true
+	l.mu.Unlock()
+
+	close(l.done)
+	l.wg.Wait()
+	return l.Flush()
+}
+
+func (l *SentryLogger) run() {
+	defer l.wg.Done()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-l.flushCh:
+		}
+		l.sendMu.Lock()
+		err := l.send()
+		l.sendMu.Unlock()
+		if err != nil {
+			l.reporter.report(err)
+		}
+	}
+}
+
+// send sends the queued events and keeps the first error for Flush,
+// l.sendMu must be held.
+func (l *SentryLogger) send() error {
+	l.mu.Lock()
+	events := l.events
+	l.events = nil
+	l.mu.Unlock()
+
+	var firstErr error
+	for _, e := range events {
+		if err := l.post(e); err != nil {
+			atomic.AddUint64(&l.dropped, 1)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	if l.sendErr == nil {
+		l.sendErr = firstErr
+	}
+	return firstErr
+}
+
+// post sends an event in an envelope request.
+func (l *SentryLogger) post(e sentryEnvelope) error {
+	l.mu.Lock()
+	limited := l.now().Before(l.limitedTill)
+	l.mu.Unlock()
+	if limited {
+		return errors.New("rate limited")
+	}
+
+	var body bytes.Buffer
+	_, _ = body.WriteString(`{"event_id":`)
+	writeJSONString(&body, e.id)
+	_, _ = body.WriteString(`,"sent_at":`)
+	writeJSONString(&body, l.now().UTC().Format(time.RFC3339Nano))
+	_, _ = body.WriteString(`,"dsn":`)
+	writeJSONString(&body, l.dsn)
+	_, _ = fmt.Fprintf(&body, "}\n{\"type\":\"event\",\"length\":%d}\n", len(e.payload))
+	_, _ = body.Write(e.payload)
+	_ = body.WriteByte('\n')
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, l.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", l.auth)
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if wait := sentryRetryAfter(resp); wait > 0 {
+		l.mu.Lock()
+		l.limitedTill = l.now().Add(wait)
+		l.mu.Unlock()
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	return fmt.Errorf("unexpected status %s", resp.Status)
+}
+
+// sentryRetryAfter returns how long events should not be sent by rate limit
+// headers of resp, only limits of all categories or error category are used.
+func sentryRetryAfter(resp *http.Response) time.Duration {
+	var wait time.Duration
+	if limits := resp.Header.Get("X-Sentry-Rate-Limits"); limits != "" {
+		for _, limit := range strings.Split(limits, ",") {
+			parts := strings.Split(strings.TrimSpace(limit), ":")
+			seconds, err := strconv.Atoi(parts[0])
+			if err != nil {
+				continue
+			}
+			if len(parts) > 1 && parts[1] != "" && !strings.Contains(";"+parts[1]+";", ";error;") {
+				continue
+			}
+			if d := time.Duration(seconds) * time.Second; d > wait {
+				wait = d
+			}
+		}
+		return wait
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		wait = time.Minute
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+			wait = time.Duration(seconds) * time.Second
+		}
+	}
+	return wait
+}
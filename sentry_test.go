@@ -0,0 +1,236 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type sentryServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	events   []map[string]interface{}
+	auth     []string
+	paths    []string
+	response func(w http.ResponseWriter)
+}
+
+func newSentryServer(t *testing.T) *sentryServer {
+	t.Helper()
+
+	s := &sentryServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sc := bufio.NewScanner(r.Body)
+		sc.Buffer(nil, 1<<20)
+		var lines []string
+		for sc.Scan() {
+			lines = append(lines, sc.Text())
+		}
+		if len(lines) != 3 || !strings.Contains(lines[1], `"type":"event"`) {
+			t.Errorf("invalid envelope %q", lines)
+			return
+		}
+		var event map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[2]), &event); err != nil {
+			t.Errorf("json.Unmarshal() error: %v", err)
+		}
+
+		s.mu.Lock()
+		s.events = append(s.events, event)
+		s.auth = append(s.auth, r.Header.Get("X-Sentry-Auth"))
+		s.paths = append(s.paths, r.URL.Path)
+		response := s.response
+		s.mu.Unlock()
+		if response != nil {
+			response(w)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *sentryServer) dsn() string {
+	return strings.Replace(s.URL, "://", "://key@", 1) + "/prefix/42"
+}
+
+// Test that SentryLogger properly reports logs as events.
+func TestSentryLogger(t *testing.T) {
+	t.Parallel()
+
+	s := newSentryServer(t)
+	l, err := NewSentryLogger(s.dsn(), SentryTagKeys("user"), SentryEnvironment("test"))
+	if err != nil {
+		t.Fatalf("NewSentryLogger() error: %v", err)
+	}
+
+	l.Log(LevelWarn, DefaultMsgKey, "ignored")
+	l.Log(LevelError, DefaultMsgKey, "query failed", "user", "u1", "rows", 3, "err", errors.New("timeout"))
+	if err := l.Close(); err != nil {
+		t.Fatalf("l.Close() error: %v", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) != 1 {
+		t.Fatalf("len(events) = %d want 1", len(s.events))
+	}
+	if got, want := s.paths[0], "/prefix/api/42/envelope/"; got != want {
+		t.Errorf("path = %q want %q", got, want)
+	}
+	if !strings.Contains(s.auth[0], "sentry_key=key") {
+		t.Errorf("X-Sentry-Auth = %q want sentry_key", s.auth[0])
+	}
+
+	e := s.events[0]
+	if e["message"] != "query failed" || e["level"] != "error" || e["environment"] != "test" {
+		t.Errorf("event = %v", e)
+	}
+	if got := e["tags"].(map[string]interface{}); got["user"] != "u1" {
+		t.Errorf("tags = %v", got)
+	}
+	if got := e["extra"].(map[string]interface{}); got["rows"] != 3.0 || len(got) != 1 {
+		t.Errorf("extra = %v", got)
+	}
+
+	exc := e["exception"].(map[string]interface{})["values"].([]interface{})[0].(map[string]interface{})
+	if exc["type"] != "*errors.errorString" || exc["value"] != "timeout" {
+		t.Errorf("exception = %v", exc)
+	}
+	frames := exc["stacktrace"].(map[string]interface{})["frames"].([]interface{})
+	last := frames[len(frames)-1].(map[string]interface{})
+	if last["function"] != "TestSentryLogger" || last["filename"] != "sentry_test.go" || last["in_app"] != true {
+		t.Errorf("last frame = %v", last)
+	}
+
+	fingerprint := e["fingerprint"].([]interface{})
+	if len(fingerprint) != 2 || fingerprint[0] != "query failed" || !strings.HasPrefix(fingerprint[1].(string), "sentry_test.go:") {
+		t.Errorf("fingerprint = %v", fingerprint)
+	}
+}
+
+// Test that SentryLogger properly limits rate of events.
+func TestSentryLoggerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	s := newSentryServer(t)
+	l, err := NewSentryLogger(s.dsn(), SentryRateLimit(2, time.Hour))
+	if err != nil {
+		t.Fatalf("NewSentryLogger() error: %v", err)
+	}
+	defer l.Close()
+
+	for i := 0; i < 3; i++ {
+		l.Log(LevelError, DefaultMsgKey, "m")
+	}
+	_ = l.Flush()
+
+	s.mu.Lock()
+	if got, want := len(s.events), 2; got != want {
+		t.Errorf("len(events) = %d want %d", got, want)
+	}
+	s.mu.Unlock()
+	if got, want := l.Dropped(), uint64(1); got != want {
+		t.Errorf("l.Dropped() = %d want %d", got, want)
+	}
+}
+
+// Test that SentryLogger properly respects rate limits responded by Sentry.
+func TestSentryLoggerServerRateLimit(t *testing.T) {
+	t.Parallel()
+
+	s := newSentryServer(t)
+	s.response = func(w http.ResponseWriter) {
+		w.Header().Set("X-Sentry-Rate-Limits", "60:error;default:organization, 3600:transaction")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}
+	l, err := NewSentryLogger(s.dsn())
+	if err != nil {
+		t.Fatalf("NewSentryLogger() error: %v", err)
+	}
+	defer l.Close()
+
+	l.Log(LevelError, DefaultMsgKey, "limited")
+	if err := l.Flush(); err == nil {
+		t.Errorf("l.Flush() succeed with status 429")
+	}
+	l.Log(LevelError, DefaultMsgKey, "dropped")
+	_ = l.Flush()
+
+	s.mu.Lock()
+	if got, want := len(s.events), 1; got != want {
+		t.Errorf("len(events) = %d want %d", got, want)
+	}
+	s.mu.Unlock()
+	if got, want := l.Dropped(), uint64(2); got != want {
+		t.Errorf("l.Dropped() = %d want %d", got, want)
+	}
+}
+
+// Test that SentryLogger reports errors of sending in background to fallback.
+func TestSentryLoggerFallback(t *testing.T) {
+	t.Parallel()
+
+	s := newSentryServer(t)
+	s.response = func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadRequest)
+	}
+	buf := &bytes.Buffer{}
+	l, err := NewSentryLogger(s.dsn(), SentryFallback(NewStdLogger(buf)))
+	if err != nil {
+		t.Fatalf("NewSentryLogger() error: %v", err)
+	}
+
+	l.Log(LevelError, DefaultMsgKey, "m")
+	for i := 0; i < 1000 && l.Dropped() == 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if err := l.Close(); err == nil {
+		t.Errorf("l.Close() succeed with status 400")
+	}
+	if got := buf.String(); !strings.HasPrefix(got, `ERROR, "msg": "golog: sentry error"`) {
+		t.Errorf("buf.String() = %q want error report", got)
+	}
+}
+
+// Test that sentryRetryAfter properly parses rate limit headers.
+func TestSentryRetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		status int
+		header http.Header
+		want   time.Duration
+	}{
+		{status: 200, header: http.Header{}, want: 0},
+		{status: 429, header: http.Header{}, want: time.Minute},
+		{status: 429, header: http.Header{"Retry-After": {"5"}}, want: 5 * time.Second},
+		{status: 200, header: http.Header{"X-Sentry-Rate-Limits": {"30::organization"}}, want: 30 * time.Second},
+		{status: 429, header: http.Header{"X-Sentry-Rate-Limits": {"30:transaction:key"}}, want: 0},
+		{status: 429, header: http.Header{"X-Sentry-Rate-Limits": {"10:error;default, 20:default;error"}}, want: 20 * time.Second},
+	}
+	for _, tt := range tests {
+		resp := &http.Response{StatusCode: tt.status, Header: tt.header}
+		if got := sentryRetryAfter(resp); got != tt.want {
+			t.Errorf("sentryRetryAfter(%d, %v) = %v want %v", tt.status, tt.header, got, tt.want)
+		}
+	}
+}
+
+// Test that NewSentryLogger properly rejects invalid dsn.
+func TestSentryLoggerInvalidDSN(t *testing.T) {
+	t.Parallel()
+
+	for _, dsn := range []string{"https://sentry.example.com/42", "https://key@sentry.example.com/", "%"} {
+		if _, err := NewSentryLogger(dsn); err == nil {
+			t.Errorf("NewSentryLogger(%q) succeed", dsn)
+		}
+	}
+}
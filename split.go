@@ -0,0 +1,86 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"sync"
+)
+
+// LevelWriter is a writer which chooses output by level of logs,
+// NewStdLogger, NewTermLogger, NewJSONLogger and NewLogfmtLogger write
+// logs through WriteLevel if their writer is a LevelWriter.
+type LevelWriter interface {
+	io.Writer
+	// WriteLevel write p of a log with level.
+	WriteLevel(level Level, p []byte) (int, error)
+}
+
+type splitWriter struct {
+	mu        sync.Mutex
+	threshold Level
+	low       io.Writer
+	high      io.Writer
+}
+
+// SplitWriter creates a writer which writes logs with level less than
+// threshold to low and others to high, e.g. INFO and below to stdout and
+// WARN and above to stderr:
+//
+//	w := golog.SplitWriter(golog.LevelWarn, os.Stdout, os.Stderr)
+//
+// Write without level writes to low.
+func SplitWriter(threshold Level, low, high io.Writer) LevelWriter {
+	return &splitWriter{threshold: threshold, low: low, high: high}
+}
+
+func (w *splitWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.low.Write(p)
+}
+
+func (w *splitWriter) WriteLevel(level Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if level < w.threshold {
+		return w.low.Write(p)
+	}
+	return w.high.Write(p)
+}
+
+// Sync flushes both writers.
+func (w *splitWriter) Sync() error {
+	return joinErrors(syncWriter(w.low), syncWriter(w.high))
+}
+
+type levelBoundWriter struct {
+	w     LevelWriter
+	level Level
+}
+
+func (w levelBoundWriter) Write(p []byte) (int, error) {
+	return w.w.WriteLevel(w.level, p)
+}
+
+// writerForLevel returns a writer which writes to w with level if w is a LevelWriter.
+func writerForLevel(w io.Writer, level Level) io.Writer {
+	if lw, ok := w.(LevelWriter); ok {
+		return levelBoundWriter{w: lw, level: level}
+	}
+	return w
+}
+
+// outputLevel write the log in buf as a line through lg, by level if writer
+// of lg is a LevelWriter.
+func outputLevel(lg *log.Logger, level Level, buf *bytes.Buffer) {
+	lw, ok := lg.Writer().(LevelWriter)
+	if !ok {
+		_ = lg.Output(0, buf.String())
+		return
+	}
+	_ = buf.WriteByte('\n')
+	_, _ = lw.WriteLevel(level, buf.Bytes())
+}
@@ -0,0 +1,72 @@
+package golog
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+// Test that built-in loggers properly write logs to SplitWriter by level.
+func TestSplitWriter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		newLogger func(w io.Writer) Logger
+		low       string
+		high      string
+	}{
+		{
+			name:      "Std",
+			newLogger: func(w io.Writer) Logger { return NewStdLogger(w) },
+			low:       "DEBUG, \"k\": \"v\"\nINFO, \"k\": \"v\"\n",
+			high:      "WARN, \"k\": \"v\"\nERROR, \"k\": \"v\"\n",
+		},
+		{
+			name:      "Term",
+			newLogger: func(w io.Writer) Logger { return NewTermLogger(w, false) },
+			low:       "[DEBUG] k:v\n[INFO] k:v\n",
+			high:      "[WARN] k:v\n[ERROR] k:v\n",
+		},
+		{
+			name:      "JSON",
+			newLogger: func(w io.Writer) Logger { return NewJSONLogger(w) },
+			low:       "{\"level\":\"DEBUG\",\"k\":\"v\"}\n{\"level\":\"INFO\",\"k\":\"v\"}\n",
+			high:      "{\"level\":\"WARN\",\"k\":\"v\"}\n{\"level\":\"ERROR\",\"k\":\"v\"}\n",
+		},
+		{
+			name:      "Logfmt",
+			newLogger: func(w io.Writer) Logger { return NewLogfmtLogger(w) },
+			low:       "level=DEBUG k=v\nlevel=INFO k=v\n",
+			high:      "level=WARN k=v\nlevel=ERROR k=v\n",
+		},
+	}
+	for _, tt := range tests {
+		var low, high bytes.Buffer
+		l := tt.newLogger(SplitWriter(LevelWarn, &low, &high))
+		for _, level := range []Level{LevelDebug, LevelInfo, LevelWarn, LevelError} {
+			l.Log(level, "k", "v")
+		}
+		if got := low.String(); got != tt.low {
+			t.Errorf("%s: low.String() = %q want %q", tt.name, got, tt.low)
+		}
+		if got := high.String(); got != tt.high {
+			t.Errorf("%s: high.String() = %q want %q", tt.name, got, tt.high)
+		}
+	}
+}
+
+// Test that SplitWriter properly writes and syncs without level.
+func TestSplitWriterWriteSync(t *testing.T) {
+	t.Parallel()
+
+	low, high := &syncBuffer{}, &syncBuffer{}
+	w := SplitWriter(LevelWarn, low, high)
+	_, _ = w.Write([]byte("x"))
+	if got := low.String(); got != "x" {
+		t.Errorf("low.String() = %q want %q", got, "x")
+	}
+	if err := Sync(NewStdLogger(w)); err != nil {
+		t.Errorf("Sync() error: %v", err)
+	}
+}
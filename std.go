@@ -8,21 +8,45 @@ import (
 	"sync"
 )
 
+// StdOption is standard logger option.
+type StdOption func(l *stdLogger)
+
+// StdDedupKeys handle duplicate keys with policy, see HandlerDedupKeys.
+func StdDedupKeys(policy DuplicateKeyPolicy) StdOption {
+	return func(l *stdLogger) {
+		l.dedupKeys = HandlerDedupKeys(policy)
+	}
+}
+
+// StdGroupSeparator set the separator between group name and keys, default is DefaultGroupSeparator.
+func StdGroupSeparator(sep string) StdOption {
+	return func(l *stdLogger) {
+		l.groupSep = sep
+	}
+}
+
 type stdLogger struct {
-	log  *log.Logger
-	pool *sync.Pool
+	log       *log.Logger
+	pool      *sync.Pool
+	dedupKeys Handler
+	groupSep  string
 }
 
 // NewStdLogger new a standard logger with writer.
-func NewStdLogger(w io.Writer) Logger {
-	return &stdLogger{
+func NewStdLogger(w io.Writer, opts ...StdOption) Logger {
+	l := &stdLogger{
 		log: log.New(w, "", 0),
 		pool: &sync.Pool{
 			New: func() interface{} {
 				return new(bytes.Buffer)
 			},
 		},
+		groupSep: DefaultGroupSeparator,
+	}
+	for _, o := range opts {
+		o(l)
 	}
+	return l
 }
 
 // Log write the kv pairs log.
@@ -31,8 +55,9 @@ func (l *stdLogger) Log(level Level, kvs ...interface{}) {
 		return
 	}
 
-	if (len(kvs) & 1) == 1 {
-		kvs = append(kvs, "KEY VALUES UNPAIRED")
+	kvs = flattenGroups(l.groupSep, normalizeKVs(DefaultKeyPolicy, kvs))
+	if l.dedupKeys != nil {
+		kvs = l.dedupKeys(level, kvs)
 	}
 
 	buf := l.pool.Get().(*bytes.Buffer)
@@ -40,7 +65,12 @@ func (l *stdLogger) Log(level Level, kvs ...interface{}) {
 	for i := 0; i < len(kvs); i += 2 {
 		_, _ = fmt.Fprintf(buf, `, "%v": "%v"`, kvs[i], kvs[i+1])
 	}
-	_ = l.log.Output(0, buf.String())
+	outputLevel(l.log, level, buf)
 	buf.Reset()
 	l.pool.Put(buf)
 }
+
+// Sync flushes buffered logs of the writer.
+func (l *stdLogger) Sync() error {
+	return syncWriter(l.log.Writer())
+}
@@ -48,3 +48,15 @@ func TestStdLogger(t *testing.T) {
 		})
 	}
 }
+
+// Test that StdDedupKeys properly handle duplicate keys.
+func TestStdLoggerDedupKeys(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := WithHandler(NewStdLogger(&buf, StdDedupKeys(DuplicateKeepLast)), HandlerFields("k1", 2))
+	l.Log(LevelInfo, "k1", 1, "k2", 2)
+	if got, want := buf.String(), `INFO, "k2": "2", "k1": "2"`+"\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
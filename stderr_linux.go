@@ -0,0 +1,20 @@
+//go:build linux
+// +build linux
+
+package golog
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// RedirectStderr redirects standard error (fd 2) of the process into file with
+// dup2, so crash dumps of the runtime, such as unrecovered panics and fatal
+// errors, are written to file too.
+//
+// The redirection applies to the file at the time of the call, it does not
+// follow rotation of the file.
+func RedirectStderr(file *os.File) error {
+	return unix.Dup3(int(file.Fd()), int(os.Stderr.Fd()), 0)
+}
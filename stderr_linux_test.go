@@ -0,0 +1,41 @@
+//go:build linux
+// +build linux
+
+package golog
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test that RedirectStderr properly redirects crash dumps into file.
+func TestRedirectStderr(t *testing.T) {
+	if name := os.Getenv("GOLOG_TEST_REDIRECT_STDERR"); name != "" {
+		f, err := os.Create(name)
+		if err != nil {
+			t.Fatalf("os.Create() error: %v", err)
+		}
+		if err := RedirectStderr(f); err != nil {
+			t.Fatalf("RedirectStderr() error: %v", err)
+		}
+		panic("boom")
+	}
+
+	name := filepath.Join(t.TempDir(), "stderr.log")
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRedirectStderr$")
+	cmd.Env = append(os.Environ(), "GOLOG_TEST_REDIRECT_STDERR="+name)
+	if out, err := cmd.CombinedOutput(); err == nil {
+		t.Fatalf("crashed process succeed: %s", out)
+	}
+
+	b, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("os.ReadFile() error: %v", err)
+	}
+	if got := string(b); !strings.Contains(got, "panic: boom") {
+		t.Errorf("stderr = %q want crash dump", got)
+	}
+}
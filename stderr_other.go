@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package golog
+
+import (
+	"errors"
+	"os"
+)
+
+// RedirectStderr always returns error since it is only supported on linux.
+func RedirectStderr(file *os.File) error {
+	return errors.New("golog: redirect stderr: not supported on this platform")
+}
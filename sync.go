@@ -0,0 +1,57 @@
+package golog
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+)
+
+// Syncer is implemented by loggers and writers which buffer logs.
+type Syncer interface {
+	// Sync flushes buffered logs.
+	Sync() error
+}
+
+// Sync flushes buffered logs of logger. Loggers which decorate or combine
+// other loggers sync all of them, so a whole logger tree can be flushed
+// with a single call on shutdown.
+func Sync(logger Logger) error {
+	if s, ok := logger.(Syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// syncWriter flushes w if it is a Syncer, standard output and standard
+// error are skipped since they are not buffered and may not support sync.
+func syncWriter(w io.Writer) error {
+	if w == os.Stdout || w == os.Stderr {
+		return nil
+	}
+	if s, ok := w.(Syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// joinErrors returns an error contains messages of all non-nil errors.
+func joinErrors(errs ...error) error {
+	var msgs []string
+	for _, err := range errs {
+		if err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	switch len(msgs) {
+	case 0:
+		return nil
+	case 1:
+		for _, err := range errs {
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
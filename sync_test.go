@@ -0,0 +1,45 @@
+package golog
+
+import (
+	"errors"
+	"testing"
+)
+
+type syncLogger struct {
+	synced int
+	err    error
+}
+
+func (l *syncLogger) Log(level Level, kvs ...interface{}) {}
+
+func (l *syncLogger) Sync() error {
+	l.synced++
+	return l.err
+}
+
+// Test that Sync properly flushes the whole logger tree.
+func TestSync(t *testing.T) {
+	t.Parallel()
+
+	l1 := &syncLogger{}
+	l2 := &syncLogger{err: errors.New("e2")}
+	l3 := &syncLogger{err: errors.New("e3")}
+
+	logger := MultiLogger(
+		WithFilter(l1, FilterLevel(LevelWarn)),
+		NewMultiLogger([]Logger{l2}, MultiRecover(Discard)),
+		RouteAll(Default(l3)),
+		Discard,
+	)
+	logger = WithHandler(logger, HandlerDefaultTimestamp)
+
+	err := Sync(logger)
+	if err == nil || err.Error() != "e2; e3" {
+		t.Errorf("Sync() error = %v want %q", err, "e2; e3")
+	}
+	for i, l := range []*syncLogger{l1, l2, l3} {
+		if l.synced != 1 {
+			t.Errorf("l%d.synced = %d want 1", i+1, l.synced)
+		}
+	}
+}
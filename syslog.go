@@ -0,0 +1,400 @@
+package golog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Facility is syslog facility.
+type Facility int
+
+// Syslog facilities defined by RFC 5424.
+const (
+	FacilityKern Facility = iota
+	FacilityUser
+	FacilityMail
+	FacilityDaemon
+	FacilityAuth
+	FacilitySyslog
+	FacilityLpr
+	FacilityNews
+	FacilityUucp
+	FacilityCron
+	FacilityAuthPriv
+	FacilityFtp
+	FacilityNtp
+	FacilitySecurity
+	FacilityConsole
+	FacilitySolarisCron
+	FacilityLocal0
+	FacilityLocal1
+	FacilityLocal2
+	FacilityLocal3
+	FacilityLocal4
+	FacilityLocal5
+	FacilityLocal6
+	FacilityLocal7
+)
+
+var (
+	// DefaultSyslogSDID is default SD-ID of STRUCTURED-DATA, 32473 is the
+	// private enterprise number reserved for documentation.
+	DefaultSyslogSDID = "golog@32473"
+	// DefaultSyslogTimeout is default timeout of dialing and writing.
+	DefaultSyslogTimeout = 5 * time.Second
+	// DefaultSyslogMinBackoff is default minimum delay before reconnecting.
+	DefaultSyslogMinBackoff = 100 * time.Millisecond
+	// DefaultSyslogMaxBackoff is default maximum delay before reconnecting.
+	DefaultSyslogMaxBackoff = 30 * time.Second
+
+	errSyslogBackoff = errors.New("golog: syslog: waiting to reconnect")
+
+	syslogLocalAddrs = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+	sdParamEscaper = strings.NewReplacer(`"`, `\"`, `\`, `\\`, `]`, `\]`)
+)
+
+// SyslogSeverity convert log level to syslog severity.
+func SyslogSeverity(level Level) int {
+	switch {
+	case level <= LevelDebug:
+		return 7 // debug
+	case level == LevelInfo:
+		return 6 // informational
+	case level == LevelWarn:
+		return 4 // warning
+	case level == LevelError:
+		return 3 // error
+	default:
+		return 2 // critical
+	}
+}
+
+// SyslogOption is syslog logger option.
+type SyslogOption func(l *SyslogLogger)
+
+// SyslogFacility set facility of syslog message, default is FacilityUser.
+func SyslogFacility(facility Facility) SyslogOption {
+	return func(l *SyslogLogger) {
+		l.facility = facility
+	}
+}
+
+// SyslogAppName set APP-NAME of syslog message, default is the program name.
+func SyslogAppName(appName string) SyslogOption {
+	return func(l *SyslogLogger) {
+		l.appName = appName
+	}
+}
+
+// SyslogHostname set HOSTNAME of syslog message, default is os.Hostname().
+func SyslogHostname(hostname string) SyslogOption {
+	return func(l *SyslogLogger) {
+		l.hostname = hostname
+	}
+}
+
+// SyslogProcID set PROCID of syslog message, default is the process id.
+func SyslogProcID(procID string) SyslogOption {
+	return func(l *SyslogLogger) {
+		l.procID = procID
+	}
+}
+
+// SyslogSDID set SD-ID of STRUCTURED-DATA, default is DefaultSyslogSDID.
+func SyslogSDID(sdID string) SyslogOption {
+	return func(l *SyslogLogger) {
+		l.sdID = sdID
+	}
+}
+
+// SyslogRFC3164 write messages in the legacy BSD format of RFC 3164,
+// kv pairs are appended to the message in logfmt format.
+func SyslogRFC3164() SyslogOption {
+	return func(l *SyslogLogger) {
+		l.rfc3164 = true
+	}
+}
+
+// SyslogBackoff set the minimum and maximum delay before reconnecting,
+// the delay doubles after each failure. Logs are dropped in the meantime.
+func SyslogBackoff(min, max time.Duration) SyslogOption {
+	return func(l *SyslogLogger) {
+		l.minBackoff = min
+		l.maxBackoff = max
+	}
+}
+
+// SyslogFallback set the logger to report errors of writing logs, default is
+// standard error. Errors are reported at most once in DefaultErrorReportInterval.
+func SyslogFallback(fallback Logger) SyslogOption {
+	return func(l *SyslogLogger) {
+		l.fallback = fallback
+	}
+}
+
+// SyslogLogger is a logger which writes logs to syslog server.
+type SyslogLogger struct {
+	mu         sync.Mutex
+	network    string
+	addr       string
+	conn       net.Conn
+	stream     bool
+	buf        bytes.Buffer
+	facility   Facility
+	appName    string
+	hostname   string
+	procID     string
+	sdID       string
+	rfc3164    bool
+	closed     bool
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	backoff    time.Duration
+	nextDial   time.Time
+	now        func() time.Time
+	fallback   Logger
+	reporter   *errorReporter
+}
+
+var _ Logger = (*SyslogLogger)(nil)
+
+// NewSyslogLogger new a logger which writes logs to syslog server at addr.
+//
+// Network is one of "unixgram", "unix", "udp" and "tcp". If both network
+// and addr are empty, the local syslog server is used. Messages are framed
+// with octet counting on stream connections, and the connection is
+// re-established with exponential backoff after write errors.
+func NewSyslogLogger(network, addr string, opts ...SyslogOption) (*SyslogLogger, error) {
+	l := &SyslogLogger{
+		network:    network,
+		addr:       addr,
+		facility:   FacilityUser,
+		procID:     strconv.Itoa(os.Getpid()),
+		sdID:       DefaultSyslogSDID,
+		minBackoff: DefaultSyslogMinBackoff,
+		maxBackoff: DefaultSyslogMaxBackoff,
+		now:        time.Now,
+	}
+	if len(os.Args) > 0 {
+		l.appName = os.Args[0][strings.LastIndexAny(os.Args[0], `/\`)+1:]
+	}
+	l.hostname, _ = os.Hostname()
+	for _, o := range opts {
+		o(l)
+	}
+	l.reporter = newErrorReporter("syslog", l.fallback)
+
+	if err := l.connect(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *SyslogLogger) connect() error {
+	if l.network == "" && l.addr == "" {
+		for _, addr := range syslogLocalAddrs {
+			for _, network := range []string{"unixgram", "unix"} {
+				if conn, err := net.DialTimeout(network, addr, DefaultSyslogTimeout); err == nil {
+					l.network, l.addr = network, addr
+					l.setConn(conn)
+					return nil
+				}
+			}
+		}
+		return errors.New("golog: syslog: no local syslog server")
+	}
+
+	conn, err := net.DialTimeout(l.network, l.addr, DefaultSyslogTimeout)
+	if err != nil {
+		return fmt.Errorf("golog: syslog: %w", err)
+	}
+	l.setConn(conn)
+	return nil
+}
+
+func (l *SyslogLogger) setConn(conn net.Conn) {
+	l.conn = conn
+	switch l.network {
+	case "tcp", "tcp4", "tcp6", "unix":
+		l.stream = true
+	default:
+		l.stream = false
+	}
+}
+
+// Log write the kv pairs log.
+func (l *SyslogLogger) Log(level Level, kvs ...interface{}) {
+	if len(kvs) == 0 {
+		return
+	}
+
+	if err := l.output(level, kvs); err != nil && err != errSyslogBackoff {
+		l.reporter.report(err)
+	}
+}
+
+// output formats the log into a message and writes it.
+func (l *SyslogLogger) output(level Level, kvs []interface{}) error {
+	kvs = normalizeKVs(DefaultKeyPolicy, kvs)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return nil
+	}
+	l.buf.Reset()
+	if l.rfc3164 {
+		l.formatRFC3164(level, kvs)
+	} else {
+		l.formatRFC5424(level, kvs)
+	}
+
+	msg := l.buf.Bytes()
+	if l.stream {
+		msg = append([]byte(strconv.Itoa(len(msg))+" "), msg...)
+	}
+	return l.write(msg)
+}
+
+// write writes msg to connection, reconnect and retry once on failure,
+// it does not reconnect until the backoff elapsed after a failure.
+func (l *SyslogLogger) write(msg []byte) error {
+	if l.conn != nil {
+		if err := l.writeConn(msg); err == nil {
+			return nil
+		}
+		_ = l.conn.Close()
+		l.conn = nil
+	}
+	if time.Now().Before(l.nextDial) {
+		return errSyslogBackoff
+	}
+	if err := l.connect(); err != nil {
+		if l.backoff *= 2; l.backoff < l.minBackoff {
+			l.backoff = l.minBackoff
+		} else if l.backoff > l.maxBackoff {
+			l.backoff = l.maxBackoff
+		}
+		l.nextDial = time.Now().Add(l.backoff)
+		return err
+	}
+	l.backoff = 0
+	return l.writeConn(msg)
+}
+
+func (l *SyslogLogger) writeConn(msg []byte) error {
+	_ = l.conn.SetWriteDeadline(time.Now().Add(DefaultSyslogTimeout))
+	_, err := l.conn.Write(msg)
+	return err
+}
+
+func syslogHeaderField(s string, maxLen int) string {
+	if s == "" {
+		return "-"
+	}
+	if len(s) > maxLen {
+		s = s[:maxLen]
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func sdName(s string) string {
+	if len(s) > 32 {
+		s = s[:32]
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || r == '=' || r == ']' || r == '"' {
+			return '_'
+		}
+		return r
+	}, s)
+}
+
+func splitMsg(kvs []interface{}) (msg interface{}, others []interface{}) {
+	others = make([]interface{}, 0, len(kvs))
+	for i := 0; i < len(kvs); i += 2 {
+		if k, ok := kvs[i].(string); ok && k == DefaultMsgKey && msg == nil {
+			msg = kvs[i+1]
+			continue
+		}
+		others = append(others, kvs[i], kvs[i+1])
+	}
+	return msg, others
+}
+
+func (l *SyslogLogger) formatRFC5424(level Level, kvs []interface{}) {
+	msg, others := splitMsg(kvs)
+
+	pri := int(l.facility)*8 + SyslogSeverity(level)
+	fmt.Fprintf(&l.buf, "<%d>1 %s %s %s %s - ", pri,
+		l.now().Format("2006-01-02T15:04:05.000000Z07:00"),
+		syslogHeaderField(l.hostname, 255),
+		syslogHeaderField(l.appName, 48),
+		syslogHeaderField(l.procID, 128),
+	)
+
+	if len(others) == 0 {
+		_ = l.buf.WriteByte('-')
+	} else {
+		_, _ = l.buf.WriteString("[" + l.sdID)
+		for i := 0; i < len(others); i += 2 {
+			fmt.Fprintf(&l.buf, ` %s="%s"`, sdName(fmt.Sprint(others[i])), sdParamEscaper.Replace(fmt.Sprint(others[i+1])))
+		}
+		_ = l.buf.WriteByte(']')
+	}
+
+	if msg != nil {
+		_ = l.buf.WriteByte(' ')
+		_, _ = fmt.Fprint(&l.buf, msg)
+	}
+}
+
+func (l *SyslogLogger) formatRFC3164(level Level, kvs []interface{}) {
+	msg, others := splitMsg(kvs)
+
+	pri := int(l.facility)*8 + SyslogSeverity(level)
+	fmt.Fprintf(&l.buf, "<%d>%s %s %s[%s]:", pri,
+		l.now().Format(time.Stamp),
+		syslogHeaderField(l.hostname, 255),
+		syslogHeaderField(l.appName, 32),
+		l.procID,
+	)
+	if msg != nil {
+		_ = l.buf.WriteByte(' ')
+		_, _ = fmt.Fprint(&l.buf, msg)
+	}
+	for i := 0; i < len(others); i += 2 {
+		_ = l.buf.WriteByte(' ')
+		_, _ = l.buf.WriteString(logfmtKey(fmt.Sprint(others[i])))
+		_ = l.buf.WriteByte('=')
+		_, _ = l.buf.WriteString(logfmtValue(fmt.Sprint(others[i+1])))
+	}
+}
+
+// Close closes the connection to syslog server.
+func (l *SyslogLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.closed = true
+	if l.conn == nil {
+		return nil
+	}
+	err := l.conn.Close()
+	l.conn = nil
+	return err
+}
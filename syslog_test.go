@@ -0,0 +1,225 @@
+package golog
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func syslogTestNow() time.Time {
+	return time.Date(2022, 10, 28, 16, 37, 50, 123456000, time.UTC)
+}
+
+func readPacket(t *testing.T, conn net.PacketConn) string {
+	t.Helper()
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("conn.ReadFrom() error: %v", err)
+	}
+	return string(buf[:n])
+}
+
+func readOctetCounted(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+
+	s, err := r.ReadString(' ')
+	if err != nil {
+		t.Fatalf("r.ReadString() error: %v", err)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		t.Fatalf("invalid octet count %q", s)
+	}
+	buf := make([]byte, n)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("r.Read() error: %v", err)
+	}
+	return string(buf)
+}
+
+var syslogTestOpts = []SyslogOption{
+	SyslogFacility(FacilityLocal0),
+	SyslogAppName("app"),
+	SyslogHostname("host"),
+	SyslogProcID("42"),
+}
+
+// Test that SyslogLogger properly writes RFC 5424 messages over UDP.
+func TestSyslogLoggerUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.ListenPacket() error: %v", err)
+	}
+	defer conn.Close()
+
+	l, err := NewSyslogLogger("udp", conn.LocalAddr().String(), syslogTestOpts...)
+	if err != nil {
+		t.Fatalf("NewSyslogLogger() error: %v", err)
+	}
+	defer l.Close()
+	l.now = syslogTestNow
+
+	tests := []struct {
+		name string
+		l    Level
+		kvs  []interface{}
+		want string
+	}{
+		{
+			name: "Message with kvs",
+			l:    LevelError,
+			kvs:  []interface{}{DefaultMsgKey, "hello", "k1", `a"b]`},
+			want: `<131>1 2022-10-28T16:37:50.123456Z host app 42 - [golog@32473 k1="a\"b\]"] hello`,
+		},
+		{
+			name: "Without message",
+			l:    LevelDebug,
+			kvs:  []interface{}{"k 1", 1},
+			want: `<135>1 2022-10-28T16:37:50.123456Z host app 42 - [golog@32473 k_1="1"]`,
+		},
+		{
+			name: "Without kvs",
+			l:    LevelFatal,
+			kvs:  []interface{}{DefaultMsgKey, "bye"},
+			want: `<130>1 2022-10-28T16:37:50.123456Z host app 42 - - bye`,
+		},
+	}
+	for _, tt := range tests {
+		l.Log(tt.l, tt.kvs...)
+		if got := readPacket(t, conn); got != tt.want {
+			t.Errorf("%s: got %q want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Test that SyslogLogger properly writes RFC 3164 messages over unix datagram socket.
+func TestSyslogLoggerUnixgramRFC3164(t *testing.T) {
+	addr := filepath.Join(t.TempDir(), "log.sock")
+	conn, err := net.ListenPacket("unixgram", addr)
+	if err != nil {
+		t.Skipf("net.ListenPacket() error: %v", err)
+	}
+	defer conn.Close()
+
+	l, err := NewSyslogLogger("unixgram", addr, append(syslogTestOpts, SyslogRFC3164())...)
+	if err != nil {
+		t.Fatalf("NewSyslogLogger() error: %v", err)
+	}
+	defer l.Close()
+	l.now = syslogTestNow
+
+	l.Log(LevelWarn, DefaultMsgKey, "hello", "k1", "v 1")
+	want := `<132>Oct 28 16:37:50 host app[42]: hello k1="v 1"`
+	if got := readPacket(t, conn); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+// Test that SyslogLogger properly frames messages and reconnects over TCP.
+func TestSyslogLoggerTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	defer ln.Close()
+
+	l, err := NewSyslogLogger("tcp", ln.Addr().String(), syslogTestOpts...)
+	if err != nil {
+		t.Fatalf("NewSyslogLogger() error: %v", err)
+	}
+	defer l.Close()
+	l.now = syslogTestNow
+
+	want := `<134>1 2022-10-28T16:37:50.123456Z host app 42 - - hello`
+	for i := 0; i < 2; i++ {
+		// the first log uses the initial connection, the second one reconnects
+		go l.Log(LevelInfo, DefaultMsgKey, "hello")
+
+		conn, err := ln.Accept()
+		if err != nil {
+			t.Fatalf("ln.Accept() error: %v", err)
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		if got := readOctetCounted(t, bufio.NewReader(conn)); got != want {
+			t.Errorf("got %q want %q", got, want)
+		}
+		conn.Close()
+
+		// break the connection, next log should reconnect
+		l.mu.Lock()
+		_ = l.conn.Close()
+		l.mu.Unlock()
+	}
+}
+
+// Test that SyslogLogger waits for backoff before reconnecting.
+func TestSyslogLoggerBackoff(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	l, err := NewSyslogLogger("tcp", ln.Addr().String(), SyslogBackoff(time.Hour, time.Hour))
+	if err != nil {
+		t.Fatalf("NewSyslogLogger() error: %v", err)
+	}
+	defer l.Close()
+	_ = ln.Close()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_ = l.conn.Close() // the next write fails and the reconnection is refused
+	if err := l.write([]byte("m1")); err == nil || err == errSyslogBackoff {
+		t.Errorf("l.write() error = %v want dial error", err)
+	}
+	if err := l.write([]byte("m2")); err != errSyslogBackoff {
+		t.Errorf("l.write() error = %v want %v", err, errSyslogBackoff)
+	}
+}
+
+// Test that SyslogLogger reports errors to fallback.
+func TestSyslogLoggerFallback(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() error: %v", err)
+	}
+	buf := &bytes.Buffer{}
+	l, err := NewSyslogLogger("tcp", ln.Addr().String(), SyslogFallback(NewStdLogger(buf)))
+	if err != nil {
+		t.Fatalf("NewSyslogLogger() error: %v", err)
+	}
+	defer l.Close()
+	_ = ln.Close()
+
+	l.mu.Lock()
+	_ = l.conn.Close()
+	l.mu.Unlock()
+	l.Log(LevelInfo, DefaultMsgKey, "m1")
+	l.Log(LevelInfo, DefaultMsgKey, "m2")
+	if got := buf.String(); strings.Count(got, "\n") != 1 || !strings.HasPrefix(got, `ERROR, "msg": "golog: syslog error"`) {
+		t.Errorf("buf.String() = %q want one error report", got)
+	}
+}
+
+// Test that SyslogSeverity properly convert level to severity.
+func TestSyslogSeverity(t *testing.T) {
+	t.Parallel()
+
+	want := map[Level]int{LevelDebug: 7, LevelInfo: 6, LevelWarn: 4, LevelError: 3, LevelFatal: 2}
+	for level, severity := range want {
+		if got := SyslogSeverity(level); got != severity {
+			t.Errorf("SyslogSeverity(%v) = %d want %d", level, got, severity)
+		}
+	}
+}
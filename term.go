@@ -2,9 +2,12 @@ package golog
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
+	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/fatih/color"
@@ -25,18 +28,91 @@ func init() {
 	}
 }
 
+// MultilineMode control how term logger render values contain line breaks.
+type MultilineMode int8
+
+const (
+	// MultilineRaw write multi-line values as is.
+	MultilineRaw MultilineMode = iota
+	// MultilineIndent start multi-line values on a new line and indent every line.
+	MultilineIndent
+	// MultilineGutter start multi-line values on a new line and prefix every line with a gutter.
+	MultilineGutter
+	// MultilineEscape escape the line breaks of multi-line values.
+	MultilineEscape
+)
+
+// PrettyMode control how term logger render structs, maps and slices.
+type PrettyMode int8
+
+const (
+	// PrettyNone render values with %v.
+	PrettyNone PrettyMode = iota
+	// PrettyFields render values with %+v, so struct field names are printed.
+	PrettyFields
+	// PrettyJSON render values as indented JSON.
+	PrettyJSON
+)
+
+var (
+	// DefaultMultilineIndent is the prefix of lines with MultilineIndent.
+	DefaultMultilineIndent = "    "
+	// DefaultMultilineGutter is the prefix of lines with MultilineGutter.
+	DefaultMultilineGutter = "  | "
+)
+
+var multilineEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+// TermOption is term logger option.
+type TermOption func(l *termLogger)
+
+// TermMultiline set how values contain line breaks are rendered. With
+// MultilineIndent and MultilineGutter, kv pairs after a multi-line value
+// start on a new line.
+func TermMultiline(mode MultilineMode) TermOption {
+	return func(l *termLogger) {
+		l.multiline = mode
+	}
+}
+
+// TermPretty set how structs, maps and slices are rendered.
+func TermPretty(mode PrettyMode) TermOption {
+	return func(l *termLogger) {
+		l.pretty = mode
+	}
+}
+
+// TermDedupKeys handle duplicate keys with policy, see HandlerDedupKeys.
+func TermDedupKeys(policy DuplicateKeyPolicy) TermOption {
+	return func(l *termLogger) {
+		l.dedupKeys = HandlerDedupKeys(policy)
+	}
+}
+
+// TermGroupSeparator set the separator between group name and keys, default is DefaultGroupSeparator.
+func TermGroupSeparator(sep string) TermOption {
+	return func(l *termLogger) {
+		l.groupSep = sep
+	}
+}
+
 type termLogger struct {
 	log              *log.Logger
 	colorful         bool
+	multiline        MultilineMode
+	pretty           PrettyMode
+	dedupKeys        Handler
+	groupSep         string
 	pool             *sync.Pool
 	defaultWriteFunc WriteFunc
 }
 
 // NewTermLogger new an optimized logger for terminal with writer.
-func NewTermLogger(w io.Writer, colorful bool) Logger {
-	return &termLogger{
+func NewTermLogger(w io.Writer, colorful bool, opts ...TermOption) Logger {
+	l := &termLogger{
 		log:      log.New(w, "", 0),
 		colorful: colorful,
+		groupSep: DefaultGroupSeparator,
 		pool: &sync.Pool{
 			New: func() interface{} {
 				return new(bytes.Buffer)
@@ -46,6 +122,74 @@ func NewTermLogger(w io.Writer, colorful bool) Logger {
 			fmt.Fprintln(w, a...)
 		},
 	}
+	for _, o := range opts {
+		o(l)
+	}
+	return l
+}
+
+// isComposite reports whether v is a struct, map, slice or array (or pointer to them)
+// which does not know how to format itself.
+func isComposite(v interface{}) bool {
+	switch v.(type) {
+	case nil, error, fmt.Stringer, fmt.Formatter, []byte:
+		return false
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return false
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() { //nolint:exhaustive
+	case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	default:
+		return false
+	}
+}
+
+// prefixLines move s to a new line and prefix every line of s with prefix.
+func prefixLines(s, prefix string) string {
+	s = strings.TrimRight(s, "\r\n")
+	return "\n" + prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
+
+// isBlock reports whether s is a multi-line value moved to new lines by format.
+func (l *termLogger) isBlock(s string) bool {
+	return (l.multiline == MultilineIndent || l.multiline == MultilineGutter) && strings.HasPrefix(s, "\n")
+}
+
+// format convert value to string with pretty and multi-line settings.
+func (l *termLogger) format(v interface{}) string {
+	var s string
+	switch {
+	case l.pretty == PrettyFields && isComposite(v):
+		s = fmt.Sprintf("%+v", v)
+	case l.pretty == PrettyJSON && isComposite(v):
+		if b, err := json.MarshalIndent(v, "", "  "); err == nil {
+			s = string(b)
+		} else {
+			s = fmt.Sprintf("%+v", v)
+		}
+	default:
+		s = fmt.Sprint(v)
+	}
+
+	if !strings.ContainsAny(s, "\r\n") {
+		return s
+	}
+	switch l.multiline {
+	case MultilineIndent:
+		return prefixLines(s, DefaultMultilineIndent)
+	case MultilineGutter:
+		return prefixLines(s, DefaultMultilineGutter)
+	case MultilineEscape:
+		return multilineEscaper.Replace(s)
+	default:
+		return s
+	}
 }
 
 func extractDefaultTSCallerMsg(kvs ...interface{}) (ts, caller, msg interface{}) {
@@ -74,8 +218,9 @@ func (l *termLogger) Log(level Level, kvs ...interface{}) {
 		return
 	}
 
-	if (len(kvs) & 1) == 1 {
-		kvs = append(kvs, "KEY VALUES UNPAIRED")
+	kvs = flattenGroups(l.groupSep, normalizeKVs(DefaultKeyPolicy, kvs))
+	if l.dedupKeys != nil {
+		kvs = l.dedupKeys(level, kvs)
 	}
 
 	ts, caller, msg := extractDefaultTSCallerMsg(kvs...)
@@ -87,8 +232,15 @@ func (l *termLogger) Log(level Level, kvs ...interface{}) {
 		_, _ = fmt.Fprintf(buf, "[%v]", caller)
 	}
 	_, _ = fmt.Fprintf(buf, "[%v]", level)
+	// block is set after a multi-line value moved to new lines,
+	// so the next value starts on a new line too
+	block := false
 	if msg != nil {
-		_, _ = fmt.Fprintf(buf, " %v", msg)
+		s := l.format(msg)
+		if block = l.isBlock(s); !block {
+			_ = buf.WriteByte(' ')
+		}
+		_, _ = buf.WriteString(s)
 	}
 
 	for i := 0; i < len(kvs); i += 2 {
@@ -96,7 +248,14 @@ func (l *termLogger) Log(level Level, kvs ...interface{}) {
 			(k == DefaultTimestampKeyName || k == DefaultCallerKeyName || k == DefaultMsgKey) {
 			continue
 		}
-		_, _ = fmt.Fprintf(buf, ` %v:%v`, kvs[i], kvs[i+1])
+		if block {
+			_ = buf.WriteByte('\n')
+		} else {
+			_ = buf.WriteByte(' ')
+		}
+		s := l.format(kvs[i+1])
+		_, _ = fmt.Fprintf(buf, `%v:%s`, kvs[i], s)
+		block = l.isBlock(s)
 	}
 
 	writeFunc := l.defaultWriteFunc
@@ -105,8 +264,13 @@ func (l *termLogger) Log(level Level, kvs ...interface{}) {
 			writeFunc = fn
 		}
 	}
-	writeFunc(l.log.Writer(), buf.String())
+	writeFunc(writerForLevel(l.log.Writer(), level), buf.String())
 
 	buf.Reset()
 	l.pool.Put(buf)
 }
+
+// Sync flushes buffered logs of the writer.
+func (l *termLogger) Sync() error {
+	return syncWriter(l.log.Writer())
+}
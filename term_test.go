@@ -2,6 +2,7 @@ package golog
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 )
 
@@ -87,3 +88,107 @@ func TestTermLogger(t *testing.T) {
 		})
 	}
 }
+
+// Test that TermMultiline properly render values contain line breaks.
+func TestTermLoggerMultiline(t *testing.T) {
+	t.Parallel()
+
+	kvs := []interface{}{DefaultMsgKey, "m1\nm2", "k1", "v1\nv2\n", "k2", "v3"}
+	tests := []struct {
+		name string
+		mode MultilineMode
+		want string
+	}{
+		{
+			name: "Raw",
+			mode: MultilineRaw,
+			want: "[INFO] m1\nm2 k1:v1\nv2\n k2:v3\n",
+		},
+		{
+			name: "Indent",
+			mode: MultilineIndent,
+			want: "[INFO]\n    m1\n    m2\nk1:\n    v1\n    v2\nk2:v3\n",
+		},
+		{
+			name: "Gutter",
+			mode: MultilineGutter,
+			want: "[INFO]\n  | m1\n  | m2\nk1:\n  | v1\n  | v2\nk2:v3\n",
+		},
+		{
+			name: "Escape",
+			mode: MultilineEscape,
+			want: `[INFO] m1\nm2 k1:v1\nv2\n k2:v3` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewTermLogger(&buf, false, TermMultiline(tt.mode))
+
+			log.Log(LevelInfo, kvs...)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("buf.String() = %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test that TermPretty properly render structs, maps and slices.
+func TestTermLoggerPretty(t *testing.T) {
+	t.Parallel()
+
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	kvs := []interface{}{"p", point{1, 2}, "s", []int{1, 2}, "e", errors.New("e1")}
+	tests := []struct {
+		name string
+		opts []TermOption
+		want string
+	}{
+		{
+			name: "None",
+			opts: []TermOption{TermPretty(PrettyNone)},
+			want: "[INFO] p:{1 2} s:[1 2] e:e1\n",
+		},
+		{
+			name: "Fields",
+			opts: []TermOption{TermPretty(PrettyFields)},
+			want: "[INFO] p:{X:1 Y:2} s:[1 2] e:e1\n",
+		},
+		{
+			name: "JSON",
+			opts: []TermOption{TermPretty(PrettyJSON)},
+			want: "[INFO] p:{\n  \"x\": 1,\n  \"y\": 2\n} s:[\n  1,\n  2\n] e:e1\n",
+		},
+		{
+			name: "JSON with escape",
+			opts: []TermOption{TermPretty(PrettyJSON), TermMultiline(MultilineEscape)},
+			want: `[INFO] p:{\n  "x": 1,\n  "y": 2\n} s:[\n  1,\n  2\n] e:e1` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			log := NewTermLogger(&buf, false, tt.opts...)
+
+			log.Log(LevelInfo, kvs...)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("buf.String() = %q want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test that TermDedupKeys properly handle duplicate keys.
+func TestTermLoggerDedupKeys(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewTermLogger(&buf, false, TermDedupKeys(DuplicateRename))
+	l.Log(LevelInfo, DefaultMsgKey, "m", "k1", 1, "k1", 2)
+	if got, want := buf.String(), "[INFO] m k1:1 k1_1:2\n"; got != want {
+		t.Errorf("buf.String() = %q want %q", got, want)
+	}
+}
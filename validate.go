@@ -0,0 +1,57 @@
+package golog
+
+import (
+	"path/filepath"
+	"reflect"
+	"strconv"
+)
+
+type validateLogger struct {
+	logger Logger
+	report func(problem string)
+}
+
+func (l *validateLogger) Log(level Level, kvs ...interface{}) {
+	file, line := caller(1)
+	site := filepath.Base(file) + ":" + strconv.Itoa(line)
+
+	if isNilLogger(l.logger) {
+		l.report(site + ": log to nil logger")
+		return
+	}
+	if problem := kvsProblem(expandGroups(kvs)); problem != "" {
+		l.report(site + ": " + problem)
+	}
+	l.logger.Log(level, kvs...)
+}
+
+// Sync flushes buffered logs of the wrapped logger.
+func (l *validateLogger) Sync() error {
+	if isNilLogger(l.logger) {
+		return nil
+	}
+	return Sync(l.logger)
+}
+
+var _ Logger = (*validateLogger)(nil)
+
+// isNilLogger reports whether logger is nil or a nil pointer.
+func isNilLogger(logger Logger) bool {
+	if logger == nil {
+		return true
+	}
+	v := reflect.ValueOf(logger)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// ValidateLogger creates a development logger that reports misuse of logger,
+// such as unpaired kvs, non-string keys and nil logger, with the call site.
+// Problems are reported to report, or panic if report is nil.
+func ValidateLogger(logger Logger, report func(problem string)) Logger {
+	if report == nil {
+		report = func(problem string) {
+			panic("golog: " + problem)
+		}
+	}
+	return &validateLogger{logger: logger, report: report}
+}
@@ -0,0 +1,53 @@
+package golog
+
+import (
+	"bytes"
+	"testing"
+)
+
+// Test that ValidateLogger properly reports misuse with call site.
+func TestValidateLogger(t *testing.T) {
+	t.Parallel()
+
+	var problems []string
+	report := func(problem string) {
+		problems = append(problems, problem)
+	}
+
+	var buf bytes.Buffer
+	l := ValidateLogger(NewStdLogger(&buf), report)
+	l.Log(LevelInfo, "k1", 1)
+	l.Log(LevelInfo, 1, 1)
+	NewHelper(l).Log(LevelInfo, "k1")
+	var nilLogger *SyslogLogger
+	ValidateLogger(nilLogger, report).Log(LevelInfo, "k1", 1)
+
+	want := []string{
+		"validate_test.go:20: non-string key 1 (int) at index 0",
+		"validate_test.go:21: key k1 without value",
+		"validate_test.go:23: log to nil logger",
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("problems = %q want %q", problems, want)
+	}
+	for i := range want {
+		if problems[i] != want[i] {
+			t.Errorf("problems[%d] = %q want %q", i, problems[i], want[i])
+		}
+	}
+	if got := bytes.Count(buf.Bytes(), []byte("\n")); got != 3 {
+		t.Errorf("buf.String() = %q want 3 lines", buf.String())
+	}
+}
+
+// Test that ValidateLogger properly panics without report.
+func TestValidateLoggerPanic(t *testing.T) {
+	t.Parallel()
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Errorf("recover() = nil want panic")
+		}
+	}()
+	ValidateLogger(Discard, nil).Log(LevelInfo, "k1")
+}